@@ -2,40 +2,62 @@ package middleware
 
 import (
 	"context"
+	"crud-app/app/i18n"
+	"crud-app/app/logger"
 	"crud-app/app/repository"
+	"crud-app/app/session"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware(userRepo repository.UserRepository, next http.Handler) http.Handler {
+// AuthMiddleware menerima token dari header Authorization (Bearer) atau dari
+// cookie sesi. Request dengan cookie sesi yang mengubah state wajib membawa
+// token CSRF yang cocok, karena browser mengirim cookie secara otomatis.
+func AuthMiddleware(userRepo repository.UserRepository, secret string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		auth := r.Header.Get("Authorization")
-		if !strings.HasPrefix(auth, "Bearer ") {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		var tokenStr string
+		viaCookie := false
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			tokenStr = strings.TrimPrefix(auth, "Bearer ")
+		} else if tokenStr = session.Token(r); tokenStr != "" {
+			viaCookie = true
+		} else {
+			logger.FromContext(r.Context()).Warn("auth failed", "reason", "missing bearer token or session cookie")
+			http.Error(w, i18n.T(r, i18n.MsgUnauthorized), http.StatusUnauthorized)
 			return
 		}
-		tokenStr := strings.TrimPrefix(auth, "Bearer ")
 
 		parsed, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("JWT_SECRET")), nil
+			return []byte(secret), nil
 		})
 		if err != nil || !parsed.Valid {
-			http.Error(w, "Token tidak sesuai", http.StatusUnauthorized)
+			logger.FromContext(r.Context()).Warn("auth failed", "reason", "invalid token", "error", err)
+			http.Error(w, i18n.T(r, i18n.MsgTokenInvalid), http.StatusUnauthorized)
 			return
 		}
 
 		claims := parsed.Claims.(jwt.MapClaims)
-		id := claims["sub"].(string)
+		id, _ := claims["sub"].(string)
 
-		user, err := userRepo.GetByID(id)
+		if viaCookie && !session.Safe(r.Method) {
+			csrf, _ := claims[session.CSRFClaim].(string)
+			if !session.ValidCSRF(r, csrf) {
+				logger.FromContext(r.Context()).Warn("auth failed", "reason", "csrf token mismatch", "sub", id)
+				http.Error(w, i18n.T(r, i18n.MsgCSRFInvalid), http.StatusForbidden)
+				return
+			}
+		}
+
+		user, err := userRepo.GetByID(r.Context(), id)
 		if err != nil {
-			http.Error(w, "User tidak ditemukan", http.StatusUnauthorized)
+			logger.FromContext(r.Context()).Warn("auth failed", "reason", "user not found", "sub", id, "error", err)
+			http.Error(w, i18n.T(r, i18n.MsgTokenUserNotFound), http.StatusUnauthorized)
 			return
 		}
 
+		logger.SetUserID(r.Context(), user.ID.Hex())
 		ctx := context.WithValue(r.Context(), "user", *user)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
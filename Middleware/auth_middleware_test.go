@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"crud-app/app/models"
+	"crud-app/app/repository"
+	"crud-app/app/session"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testSecret = "test-secret-at-least-32-characters!"
+
+// userRepoStub hanya mengimplementasikan GetByID yang dipakai AuthMiddleware.
+type userRepoStub struct {
+	repository.UserRepository
+	user models.User
+}
+
+func (s userRepoStub) GetByID(_ context.Context, id string) (*models.User, error) {
+	if id != s.user.ID.Hex() {
+		return nil, errors.New("not found")
+	}
+	u := s.user
+	return &u, nil
+}
+
+func signToken(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	user := models.User{ID: primitive.NewObjectID(), Username: "budi", Role: "admin"}
+	const csrf = "csrf-token-1"
+	exp := time.Now().Add(time.Hour).Unix()
+
+	bearer := signToken(t, testSecret, jwt.MapClaims{"sub": user.ID.Hex(), "exp": exp})
+	cookieToken := signToken(t, testSecret, jwt.MapClaims{"sub": user.ID.Hex(), "exp": exp, session.CSRFClaim: csrf})
+	noClaimToken := bearer
+	expired := signToken(t, testSecret, jwt.MapClaims{"sub": user.ID.Hex(), "exp": time.Now().Add(-time.Minute).Unix(), session.CSRFClaim: csrf})
+	forged := signToken(t, "dev-insecure-secret", jwt.MapClaims{"sub": user.ID.Hex(), "exp": exp, session.CSRFClaim: csrf})
+	unknownUser := signToken(t, testSecret, jwt.MapClaims{"sub": primitive.NewObjectID().Hex(), "exp": exp})
+	unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": user.ID.Hex(), "exp": exp}).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+	tests := []struct {
+		name       string
+		method     string
+		bearer     string
+		session    string
+		csrfCookie string
+		csrfHeader string
+		status     int
+	}{
+		{name: "no credentials", method: "GET", status: http.StatusUnauthorized},
+		{name: "bearer token", method: "POST", bearer: bearer, status: http.StatusOK},
+		{name: "bearer needs no csrf even with cookie present", method: "DELETE", bearer: bearer, session: cookieToken, status: http.StatusOK},
+		{name: "bearer with bad signature", method: "GET", bearer: forged, status: http.StatusUnauthorized},
+		{name: "unsigned token", method: "GET", bearer: unsigned, status: http.StatusUnauthorized},
+		{name: "expired token", method: "GET", session: expired, status: http.StatusUnauthorized},
+		{name: "unknown user", method: "GET", bearer: unknownUser, status: http.StatusUnauthorized},
+		{name: "cookie GET skips csrf", method: "GET", session: cookieToken, status: http.StatusOK},
+		{name: "cookie HEAD skips csrf", method: "HEAD", session: cookieToken, status: http.StatusOK},
+		{
+			name: "cookie POST with csrf", method: "POST", session: cookieToken,
+			csrfCookie: csrf, csrfHeader: csrf, status: http.StatusOK,
+		},
+		{
+			name: "cookie POST without csrf header", method: "POST", session: cookieToken,
+			csrfCookie: csrf, status: http.StatusForbidden,
+		},
+		{
+			name: "cookie PUT without csrf cookie", method: "PUT", session: cookieToken,
+			csrfHeader: csrf, status: http.StatusForbidden,
+		},
+		{
+			name: "cookie DELETE header does not match cookie", method: "DELETE", session: cookieToken,
+			csrfCookie: csrf, csrfHeader: "other", status: http.StatusForbidden,
+		},
+		{
+			name: "cookie POST header and cookie do not match claim", method: "POST", session: cookieToken,
+			csrfCookie: "attacker", csrfHeader: "attacker", status: http.StatusForbidden,
+		},
+		{
+			name: "cookie POST with token lacking csrf claim", method: "POST", session: noClaimToken,
+			csrfCookie: csrf, csrfHeader: csrf, status: http.StatusForbidden,
+		},
+		{
+			name: "invalid cookie token", method: "POST", session: forged,
+			csrfCookie: csrf, csrfHeader: csrf, status: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *models.User
+			h := AuthMiddleware(userRepoStub{user: user}, testSecret, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				u, _ := r.Context().Value("user").(models.User)
+				got = &u
+			}))
+
+			req := httptest.NewRequest(tt.method, "/api/v1/alumni", nil)
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			if tt.session != "" {
+				req.AddCookie(&http.Cookie{Name: session.CookieName, Value: tt.session})
+			}
+			if tt.csrfCookie != "" {
+				req.AddCookie(&http.Cookie{Name: session.CSRFCookieName, Value: tt.csrfCookie})
+			}
+			if tt.csrfHeader != "" {
+				req.Header.Set(session.CSRFHeader, tt.csrfHeader)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status == http.StatusOK && (got == nil || got.ID != user.ID) {
+				t.Errorf("user in context = %+v, want %s", got, user.ID.Hex())
+			}
+			if tt.status != http.StatusOK && got != nil {
+				t.Error("next handler called for rejected request")
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions mengatur CORS. AllowedOrigins boleh berisi "*" (semua origin,
+// tidak boleh digabung dengan AllowCredentials) atau pola subdomain seperti
+// "https://*.example.com".
+type CORSOptions struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORS harus dipasang di luar router: preflight OPTIONS dijawab di sini
+// sehingga tidak kena 405 dari route mux yang dibatasi method-nya.
+func CORS(opts CORSOptions, next http.Handler) http.Handler {
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+	exposed := strings.Join(opts.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(opts.MaxAge.Seconds()))
+	allowAny := containsString(opts.AllowedOrigins, "*")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		listed := originAllowed(opts.AllowedOrigins, origin)
+		if (!listed && !allowAny) ||
+			(preflight && !containsString(opts.AllowedMethods, r.Header.Get("Access-Control-Request-Method"))) {
+			// Tanpa header CORS browser akan memblokir; request biasa tetap
+			// dilayani karena bisa saja datang dari client non-browser.
+			if preflight {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Credentials hanya untuk origin yang terdaftar; origin yang lolos
+		// lewat "*" tidak pernah dipantulkan bersama cookie.
+		if listed {
+			h.Set("Access-Control-Allow-Origin", origin)
+			if opts.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+		} else {
+			h.Set("Access-Control-Allow-Origin", "*")
+		}
+
+		if preflight {
+			h.Set("Access-Control-Allow-Methods", methods)
+			h.Set("Access-Control-Allow-Headers", headers)
+			if opts.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if exposed != "" {
+			h.Set("Access-Control-Expose-Headers", exposed)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// originAllowed melaporkan apakah origin terdaftar secara eksplisit atau
+// lewat pola subdomain; "*" ditangani terpisah oleh CORS.
+func originAllowed(allowed []string, origin string) bool {
+	for _, a := range allowed {
+		if a == "*" {
+			continue
+		}
+		if strings.EqualFold(a, origin) {
+			return true
+		}
+		// Pola "https://*.example.com" cocok dengan "https://app.example.com"
+		if prefix, suffix, ok := strings.Cut(a, "*"); ok {
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+				len(origin) > len(prefix)+len(suffix) &&
+				!strings.ContainsAny(origin[len(prefix):len(origin)-len(suffix)], "/:") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOriginAllowed(t *testing.T) {
+	allowed := []string{"https://app.example.org", "https://*.example.com"}
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.example.org", true},
+		{"https://APP.example.org", true},
+		{"https://api.example.com", true},
+		// masih subdomain example.com, jadi dikuasai pemilik example.com
+		{"https://evil.com.example.com", true},
+		{"https://a.b.example.com", true},
+		{"https://example.com", false},
+		{"https://example.com.evil.com", false},
+		{"https://evilexample.com", false},
+		{"http://api.example.com", false},
+		{"http://app.example.org", false},
+		{"https://api.example.com:8443", false},
+		{"https://evil.com:443.example.com", false},
+		{"https://evil.com/.example.com", false},
+		{"https://app.example.org.evil.com", false},
+		{"null", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := originAllowed(allowed, tt.origin); got != tt.want {
+			t.Errorf("originAllowed(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+
+	if originAllowed([]string{"*"}, "https://any.example.net") {
+		t.Error(`"*" must not count as an explicitly listed origin`)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	base := CORSOptions{
+		AllowedOrigins: []string{"https://app.example.org", "https://*.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		ExposedHeaders: []string{"X-Request-ID"},
+		MaxAge:         10 * time.Minute,
+	}
+	withCredentials := base
+	withCredentials.AllowCredentials = true
+	anyOrigin := base
+	anyOrigin.AllowedOrigins = []string{"*"}
+	// ditolak config.Validate, tapi middleware tetap tidak boleh
+	// memantulkan origin sembarang bersama credentials
+	anyWithCredentials := anyOrigin
+	anyWithCredentials.AllowCredentials = true
+
+	tests := []struct {
+		name          string
+		opts          CORSOptions
+		method        string
+		origin        string
+		requestMethod string // Access-Control-Request-Method
+		status        int
+		nextCalled    bool
+		want          map[string]string
+	}{
+		{
+			name: "no origin", opts: base, method: "GET",
+			status: 200, nextCalled: true,
+			want: map[string]string{"Access-Control-Allow-Origin": "", "Vary": ""},
+		},
+		{
+			name: "allowed origin", opts: base, method: "GET", origin: "https://app.example.org",
+			status: 200, nextCalled: true,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.org",
+				"Access-Control-Allow-Credentials": "",
+				"Access-Control-Expose-Headers":    "X-Request-ID",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name: "subdomain pattern with credentials", opts: withCredentials, method: "GET", origin: "https://api.example.com",
+			status: 200, nextCalled: true,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      "https://api.example.com",
+				"Access-Control-Allow-Credentials": "true",
+			},
+		},
+		{
+			name: "disallowed origin still served", opts: withCredentials, method: "GET", origin: "https://example.com.evil.com",
+			status: 200, nextCalled: true,
+			want: map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Credentials": ""},
+		},
+		{
+			name: "scheme mismatch", opts: withCredentials, method: "GET", origin: "http://app.example.org",
+			status: 200, nextCalled: true,
+			want: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name: "null origin", opts: withCredentials, method: "GET", origin: "null",
+			status: 200, nextCalled: true,
+			want: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name: "any origin", opts: anyOrigin, method: "GET", origin: "https://any.example.net",
+			status: 200, nextCalled: true,
+			want: map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Credentials": ""},
+		},
+		{
+			name: "any origin with credentials does not reflect origin", opts: anyWithCredentials, method: "GET", origin: "https://evil.example.net",
+			status: 200, nextCalled: true,
+			want: map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Credentials": ""},
+		},
+		{
+			name: "any origin with credentials and null origin", opts: anyWithCredentials, method: "GET", origin: "null",
+			status: 200, nextCalled: true,
+			want: map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Credentials": ""},
+		},
+		{
+			name: "preflight", opts: withCredentials, method: "OPTIONS", origin: "https://app.example.org", requestMethod: "POST",
+			status: http.StatusNoContent,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.org",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Allow-Methods":     "GET, POST",
+				"Access-Control-Allow-Headers":     "Authorization, Content-Type",
+				"Access-Control-Max-Age":           "600",
+				"Access-Control-Expose-Headers":    "",
+			},
+		},
+		{
+			name: "preflight with disallowed method", opts: withCredentials, method: "OPTIONS", origin: "https://app.example.org", requestMethod: "DELETE",
+			status: http.StatusNoContent,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			name: "preflight from disallowed origin", opts: withCredentials, method: "OPTIONS", origin: "https://evil.com", requestMethod: "GET",
+			status: http.StatusNoContent,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			name: "plain OPTIONS is not a preflight", opts: base, method: "OPTIONS", origin: "https://app.example.org",
+			status: 200, nextCalled: true,
+			want: map[string]string{"Access-Control-Allow-Origin": "https://app.example.org", "Access-Control-Allow-Methods": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			h := CORS(tt.opts, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+
+			req := httptest.NewRequest(tt.method, "/api/v1/alumni", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if called != tt.nextCalled {
+				t.Errorf("next called = %v, want %v", called, tt.nextCalled)
+			}
+			for k, v := range tt.want {
+				if got := rec.Header().Get(k); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+		})
+	}
+}
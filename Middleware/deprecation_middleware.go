@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Deprecated menandai route lama dengan header Deprecation (RFC 9745),
+// Sunset (RFC 8594) dan Link ke route pengganti. successor adalah template
+// path mux; variabel seperti {id} diisi dari request.
+func Deprecated(deprecatedAt, sunset time.Time, successor string, next http.Handler) http.Handler {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetHeader := sunset.UTC().Format(http.TimeFormat)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		link := successor
+		for k, v := range mux.Vars(r) {
+			link = strings.ReplaceAll(link, "{"+k+"}", v)
+		}
+
+		h := w.Header()
+		h.Set("Deprecation", deprecation)
+		h.Set("Sunset", sunsetHeader)
+		h.Add("Link", "<"+link+`>; rel="successor-version"`)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"crud-app/app/metrics"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Metrics mencatat jumlah dan latency request per template route, method
+// dan status. Label memakai template ("/alumni/{id}") agar jumlah seri
+// metric tidak bertambah untuk setiap ID.
+func Metrics(router *mux.Router, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(rec, r)
+
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		metrics.ObserveHTTP(routeTemplate(router, r), r.Method, strconv.Itoa(rec.status), time.Since(start))
+	})
+}
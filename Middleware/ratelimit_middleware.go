@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"crud-app/app/i18n"
+	"crud-app/app/logger"
+	"crud-app/app/models"
+	"crud-app/app/ratelimit"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIKeyHeader dipakai sebagai key rate limit untuk policy dengan key "api_key".
+const APIKeyHeader = "X-API-Key"
+
+// RateLimit menerapkan policy bernama policy dari limiter. Header
+// RateLimit-* selalu dikirim; jika token habis, balas 429 dengan Retry-After.
+// Untuk policy dengan key "user", pasang di dalam AuthMiddleware.
+// Jika store gagal (misalnya Mongo mati), request tetap diteruskan.
+func RateLimit(limiter *ratelimit.Limiter, policy string, next http.Handler) http.Handler {
+	p, ok := limiter.Policy(policy)
+	if !ok {
+		// Policy tidak dikonfigurasi: route ini tidak dibatasi.
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, err := limiter.Allow(r.Context(), p, rateLimitKey(r, p.Key, limiter.TrustProxy))
+		if err != nil {
+			logger.FromContext(r.Context()).Warn("rate limit store failed, allowing request", "policy", p.Name, "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Policy", strconv.Itoa(res.Limit)+";w="+strconv.Itoa(int(p.Window.Seconds())))
+		h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		h.Set("RateLimit-Reset", ceilSeconds(res.Reset))
+
+		if !res.Allowed {
+			logger.FromContext(r.Context()).Warn("rate limited", "policy", p.Name)
+			h.Set("Retry-After", ceilSeconds(res.RetryAfter))
+			http.Error(w, i18n.T(r, i18n.MsgTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitKey memilih identitas client sesuai jenis key policy. Jika
+// identitas itu tidak ada (belum login, tanpa API key), dipakai IP.
+func rateLimitKey(r *http.Request, kind string, trustProxy bool) string {
+	switch kind {
+	case ratelimit.KeyUser:
+		if u, ok := r.Context().Value("user").(models.User); ok {
+			return "user:" + u.ID.Hex()
+		}
+	case ratelimit.KeyAPIKey:
+		if key := r.Header.Get(APIKeyHeader); key != "" {
+			// Simpan hash saja agar API key tidak tersimpan di store.
+			sum := sha256.Sum256([]byte(key))
+			return "key:" + hex.EncodeToString(sum[:8])
+		}
+	}
+	return "ip:" + clientIP(r, trustProxy)
+}
+
+// clientIP mengambil IP client. X-Forwarded-For hanya dipercaya jika
+// server berada di belakang proxy yang dikonfigurasi (trustProxy).
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			return strings.TrimSpace(strings.Split(xff, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
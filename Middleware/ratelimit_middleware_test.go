@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"context"
+	"crud-app/app/models"
+	"crud-app/app/ratelimit"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// stubStore mengembalikan res (atau err) dan mencatat key yang diminta.
+type stubStore struct {
+	res  ratelimit.Result
+	err  error
+	keys []string
+}
+
+func (s *stubStore) Take(_ context.Context, key string, _ ratelimit.Policy) (ratelimit.Result, error) {
+	s.keys = append(s.keys, key)
+	return s.res, s.err
+}
+
+func newTestLimiter(t *testing.T, store ratelimit.Store, trustProxy bool, key string) *ratelimit.Limiter {
+	t.Helper()
+	l, err := ratelimit.NewLimiter(store, ratelimit.Policy{Name: "default", Requests: 10, Window: time.Minute, Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.TrustProxy = trustProxy
+	return l
+}
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+func TestRateLimitHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		res        ratelimit.Result
+		status     int
+		remaining  string
+		reset      string
+		retryAfter string
+	}{
+		{
+			name:   "allowed",
+			res:    ratelimit.Result{Allowed: true, Limit: 10, Remaining: 7, Reset: 18 * time.Second},
+			status: http.StatusOK, remaining: "7", reset: "18",
+		},
+		{
+			name:   "reset rounds up",
+			res:    ratelimit.Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 5100 * time.Millisecond},
+			status: http.StatusOK, remaining: "9", reset: "6",
+		},
+		{
+			name:   "limited",
+			res:    ratelimit.Result{Allowed: false, Limit: 10, Remaining: 0, RetryAfter: 4200 * time.Millisecond, Reset: time.Minute},
+			status: http.StatusTooManyRequests, remaining: "0", reset: "60", retryAfter: "5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := RateLimit(newTestLimiter(t, &stubStore{res: tt.res}, false, ratelimit.KeyIP), "default", okHandler)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", "/alumni", nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			want := map[string]string{
+				"RateLimit-Policy":    "10;w=60",
+				"RateLimit-Limit":     "10",
+				"RateLimit-Remaining": tt.remaining,
+				"RateLimit-Reset":     tt.reset,
+				"Retry-After":         tt.retryAfter,
+			}
+			for k, v := range want {
+				if got := rec.Header().Get(k); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitExhaustsBucket(t *testing.T) {
+	l, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(time.Minute),
+		ratelimit.Policy{Name: "auth", Requests: 2, Window: time.Minute, Key: ratelimit.KeyIP})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := RateLimit(l, "auth", okHandler)
+
+	var codes []int
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", "/login", nil))
+		codes = append(codes, rec.Code)
+		if i == 2 && rec.Header().Get("Retry-After") != "30" {
+			t.Errorf("Retry-After = %q, want 30", rec.Header().Get("Retry-After"))
+		}
+	}
+	if codes[0] != 200 || codes[1] != 200 || codes[2] != http.StatusTooManyRequests {
+		t.Errorf("status codes = %v, want [200 200 429]", codes)
+	}
+}
+
+func TestRateLimitFailsOpen(t *testing.T) {
+	h := RateLimit(newTestLimiter(t, &stubStore{err: errors.New("mongo down")}, false, ratelimit.KeyIP), "default", okHandler)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/alumni", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("store error: status %d, headers %v; want request passed through", rec.Code, rec.Header())
+	}
+}
+
+func TestRateLimitUnknownPolicyIsUnlimited(t *testing.T) {
+	store := &stubStore{}
+	h := RateLimit(newTestLimiter(t, store, false, ratelimit.KeyIP), "list", okHandler)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/alumni", nil))
+	if len(store.keys) != 0 {
+		t.Errorf("store called for unconfigured policy: %v", store.keys)
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	user := models.User{ID: primitive.NewObjectID()}
+	tests := []struct {
+		name       string
+		kind       string
+		trustProxy bool
+		remote     string
+		header     map[string]string
+		user       bool
+		want       string
+	}{
+		{name: "remote addr", kind: ratelimit.KeyIP, remote: "10.0.0.1:5555", want: "ip:10.0.0.1"},
+		{name: "ipv6 remote addr", kind: ratelimit.KeyIP, remote: "[::1]:5555", want: "ip:::1"},
+		{
+			name: "forwarded for ignored without trust", kind: ratelimit.KeyIP, remote: "10.0.0.1:5555",
+			header: map[string]string{"X-Forwarded-For": "203.0.113.9"}, want: "ip:10.0.0.1",
+		},
+		{
+			name: "forwarded for trusted", kind: ratelimit.KeyIP, trustProxy: true, remote: "10.0.0.1:5555",
+			header: map[string]string{"X-Forwarded-For": " 203.0.113.9 , 10.0.0.2"}, want: "ip:203.0.113.9",
+		},
+		{
+			name: "trusted proxy without header", kind: ratelimit.KeyIP, trustProxy: true, remote: "10.0.0.1:5555",
+			want: "ip:10.0.0.1",
+		},
+		{name: "user", kind: ratelimit.KeyUser, remote: "10.0.0.1:5555", user: true, want: "user:" + user.ID.Hex()},
+		{name: "anonymous user falls back to ip", kind: ratelimit.KeyUser, remote: "10.0.0.1:5555", want: "ip:10.0.0.1"},
+		{
+			name: "api key is hashed", kind: ratelimit.KeyAPIKey, remote: "10.0.0.1:5555",
+			header: map[string]string{APIKeyHeader: "secret"}, want: "key:2bb80d537b1da3e3",
+		},
+		{name: "missing api key falls back to ip", kind: ratelimit.KeyAPIKey, remote: "10.0.0.1:5555", want: "ip:10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &stubStore{res: ratelimit.Result{Allowed: true}}
+			h := RateLimit(newTestLimiter(t, store, tt.trustProxy, tt.kind), "default", okHandler)
+
+			req := httptest.NewRequest("GET", "/alumni", nil)
+			req.RemoteAddr = tt.remote
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			if tt.user {
+				req = req.WithContext(context.WithValue(req.Context(), "user", user))
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			want := "default:" + tt.kind + ":" + tt.want
+			if len(store.keys) != 1 || store.keys[0] != want {
+				t.Errorf("store keys = %v, want [%s]", store.keys, want)
+			}
+		})
+	}
+}
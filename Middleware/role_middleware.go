@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"crud-app/app/i18n"
+	"crud-app/app/logger"
 	"crud-app/app/models"
 	"net/http"
 )
@@ -9,7 +11,8 @@ func RoleMiddleware(role string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		u := r.Context().Value("user").(models.User)
 		if u.Role != role {
-			http.Error(w, "Forbidden", http.StatusForbidden)
+			logger.FromContext(r.Context()).Warn("access denied", "required_role", role, "role", u.Role)
+			http.Error(w, i18n.T(r, i18n.MsgForbidden), http.StatusForbidden)
 			return
 		}
 		next.ServeHTTP(w, r)
@@ -1,10 +1,12 @@
 package service
 
 import (
+	"crud-app/app/tracing"
 	"encoding/json"
 	"net/http"
-	"strconv"
 
+	"crud-app/app/i18n"
+	"crud-app/app/metrics"
 	"crud-app/app/models"
 	"crud-app/app/repository"
 
@@ -19,61 +21,49 @@ func NewUserHandler(repo repository.UserRepository) *UserService {
 	return &UserService{Repo: repo}
 }
 
-
 func (h *UserService) GetUsers(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "UserService.GetUsers")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	// Ambil query params
-	query := r.URL.Query()
-	page, _ := strconv.Atoi(query.Get("page"))
-	if page == 0 {
-		page = 1
-	}
-	limit, _ := strconv.Atoi(query.Get("limit"))
-	if limit == 0 {
-		limit = 10
-	}
-	search := query.Get("search")
-	sortBy := query.Get("sortBy")
-	if sortBy == "" {
-		sortBy = "id"
-	}
-	order := query.Get("order")
-	if order == "" {
-		order = "asc"
+	q, ok := parseQuery(w, r, repository.UserSchema)
+	if !ok {
+		return
 	}
 
 	// Ambil data dari repository
-	users, total, err := h.Repo.GetUser(search, sortBy, order, page, limit)
+	users, page, err := h.Repo.GetUser(r.Context(), q)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeRepoError(w, r, err, i18n.MsgInternalError)
 		return
 	}
 
 	response := models.UserResponse{
 		Data: users,
-		Meta: models.MetaInfo{
-			Page:   page,
-			Limit:  limit,
-			Total:  total,
-			Pages:  (total + limit - 1) / limit,
-			SortBy: sortBy,
-			Order:  order,
-			Search: search,
-		},
+		Meta: listMeta(w, r, q, page),
 	}
 
 	// Set header dan kirim response JSON
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	_, encodeSpan := tracing.Start(ctx, "json.Encode")
 	json.NewEncoder(w).Encode(response)
+	encodeSpan.End()
 }
 
 func (h *UserService) SoftDeleteUser(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "UserService.SoftDeleteUser")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	id := mux.Vars(r)["id"]
 
-	if err := h.Repo.SoftDelete(id); err != nil {
-		http.Error(w, "Failed to soft delete user", http.StatusInternalServerError)
+	if err := h.Repo.SoftDelete(r.Context(), id); err != nil {
+		writeRepoError(w, r, err, i18n.MsgUserSoftDeleteFailed)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"message": "User soft deleted"})
+	metrics.SoftDeletes.WithLabelValues("user", "single").Inc()
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgUserSoftDeleted)})
 }
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"crud-app/app/i18n"
+	"crud-app/config"
+	"net/http"
+)
+
+type AdminService struct {
+	cfg *config.Config
+}
+
+func NewAdminService(cfg *config.Config) *AdminService {
+	return &AdminService{cfg: cfg}
+}
+
+// GetConfig menampilkan konfigurasi aktif dengan rahasia yang sudah disamarkan.
+func (h *AdminService) GetConfig(w http.ResponseWriter, r *http.Request) {
+	body, err := h.cfg.MarshalRedacted()
+	if err != nil {
+		http.Error(w, i18n.T(r, i18n.MsgInternalError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
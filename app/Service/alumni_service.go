@@ -1,11 +1,13 @@
 package service
 
 import (
+	"crud-app/app/i18n"
 	"crud-app/app/models"
+	"crud-app/app/query"
 	"crud-app/app/repository"
+	"crud-app/app/tracing"
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/gorilla/mux"
 )
@@ -28,95 +30,176 @@ func NewAlumniService(r repository.AlumniRepository) *AlumniService {
 // }
 
 func (h *AlumniService) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "AlumniService.GetByID")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	include, ok := parseInclude(w, r, includePekerjaan)
+	if !ok {
+		return
+	}
+	fields, ok := parseFields(w, r, repository.AlumniFields)
+	if !ok {
+		return
+	}
+
 	id := mux.Vars(r)["id"]
-	alumni, err := h.repo.FindByID(id)
+	var alumni interface{}
+	var err error
+	if include[includePekerjaan] {
+		alumni, err = h.repo.FindByIDWithPekerjaan(r.Context(), id, withPrivacy(fields))
+	} else {
+		alumni, err = h.repo.FindByID(r.Context(), id, withPrivacy(fields))
+	}
 	if err != nil {
-		http.Error(w, "Alumni not found", http.StatusNotFound)
+		http.Error(w, i18n.T(r, i18n.MsgAlumniNotFound), http.StatusNotFound)
 		return
 	}
-	json.NewEncoder(w).Encode(alumni)
+	body, err := sparse(alumni, fields, alumniPolicy(r), includedRelations(include)...)
+	if err != nil {
+		writeRepoError(w, r, err, i18n.MsgInternalError)
+		return
+	}
+	json.NewEncoder(w).Encode(body)
 }
 
 func (h *AlumniService) Create(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "AlumniService.Create")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	var a models.Alumni
 	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
-		http.Error(w, "Invalid input", http.StatusBadRequest)
+		http.Error(w, i18n.T(r, i18n.MsgInvalidInput), http.StatusBadRequest)
 		return
 	}
-	if err := h.repo.Create(&a); err != nil {
-		http.Error(w, "Failed to create alumni", http.StatusInternalServerError)
+	if err := h.repo.Create(r.Context(), &a); err != nil {
+		writeRepoError(w, r, err, i18n.MsgAlumniCreateFailed)
 		return
 	}
 	json.NewEncoder(w).Encode(a)
 }
 
 func (h *AlumniService) Update(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "AlumniService.Update")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	id := mux.Vars(r)["id"]
 	var a models.Alumni
 	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
-		http.Error(w, "Invalid input", http.StatusBadRequest)
+		http.Error(w, i18n.T(r, i18n.MsgInvalidInput), http.StatusBadRequest)
 		return
 	}
-	if err := h.repo.Update(id, &a); err != nil {
-		http.Error(w, "Failed to update alumni", http.StatusInternalServerError)
+	if err := h.repo.Update(r.Context(), id, &a); err != nil {
+		writeRepoError(w, r, err, i18n.MsgAlumniUpdateFailed)
 		return
 	}
-	json.NewEncoder(w).Encode(map[string]string{"message": "Updated successfully"})
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgUpdated)})
 }
 
 func (h *AlumniService) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "AlumniService.Delete")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	id := mux.Vars(r)["id"]
-	if err := h.repo.Delete(id); err != nil {
-		http.Error(w, "Failed to delete alumni", http.StatusInternalServerError)
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		writeRepoError(w, r, err, i18n.MsgAlumniDeleteFailed)
 		return
 	}
-	json.NewEncoder(w).Encode(map[string]string{"message": "Deleted successfully"})
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgDeleted)})
 }
 
 func (h *AlumniService) GetAlumni(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
+	ctx, span := tracing.Start(r.Context(), "AlumniService.GetAlumni")
+	defer span.End()
+	r = r.WithContext(ctx)
 
-	page, _ := strconv.Atoi(query.Get("page"))
-	if page == 0 {
-		page = 1
+	include, ok := parseInclude(w, r, includePekerjaan)
+	if !ok {
+		return
 	}
-
-	limit, _ := strconv.Atoi(query.Get("limit"))
-	if limit == 0 {
-		limit = 10
+	q, ok := parseQuery(w, r, alumniSchema(r))
+	if !ok {
+		return
 	}
-
-	search := query.Get("search")
-	sortBy := query.Get("sortBy")
-	if sortBy == "" {
-		sortBy = "id"
+	fields, ok := parseFields(w, r, repository.AlumniFields)
+	if !ok {
+		return
 	}
-
-	order := query.Get("order")
-	if order == "" {
-		order = "asc"
+	q.Fields = withPrivacy(fields)
+
+	var alumni interface{}
+	var page query.Page
+	var err error
+	if include[includePekerjaan] {
+		alumni, page, err = h.repo.GetAlumniWithPekerjaan(r.Context(), q)
+	} else {
+		alumni, page, err = h.repo.GetAlumni(r.Context(), q)
+	}
+	if err != nil {
+		writeRepoError(w, r, err, i18n.MsgInternalError)
+		return
+	}
+	data, err := sparse(alumni, fields, alumniPolicy(r), includedRelations(include)...)
+	if err != nil {
+		writeRepoError(w, r, err, i18n.MsgInternalError)
+		return
+	}
+	response := map[string]interface{}{
+		"data": data,
+		"meta": listMeta(w, r, q, page),
 	}
 
-	alumni, total, err := h.repo.GetAlumni(search, sortBy, order, page, limit)
+	w.Header().Set("Content-Type", "application/json")
+	_, encodeSpan := tracing.Start(ctx, "json.Encode")
+	json.NewEncoder(w).Encode(response)
+	encodeSpan.End()
+}
+
+// GetPrivacy mengembalikan pengaturan privasi alumni. Hanya untuk alumni
+// itu sendiri dan admin.
+func (h *AlumniService) GetPrivacy(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "AlumniService.GetPrivacy")
+	defer span.End()
+	r = r.WithContext(ctx)
 
+	id := mux.Vars(r)["id"]
+	if !canManageAlumni(r, id) {
+		http.Error(w, i18n.T(r, i18n.MsgForbidden), http.StatusForbidden)
+		return
+	}
+	p, err := h.repo.GetPrivacy(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeRepoError(w, r, err, i18n.MsgInternalError)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
 
-	response := models.AlumniResponse{
-		Data: alumni,
-		Meta: models.MetaInfo{
-			Page:   page,
-			Limit:  limit,
-			Total:  total,
-			Pages:  (total + limit - 1) / limit,
-			SortBy: sortBy,
-			Order:  order,
-			Search: search,
-		},
-	}
+// UpdatePrivacy mengganti pengaturan privasi alumni: kontak mana yang boleh
+// dilihat user lain.
+func (h *AlumniService) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "AlumniService.UpdatePrivacy")
+	defer span.End()
+	r = r.WithContext(ctx)
 
+	id := mux.Vars(r)["id"]
+	if !canManageAlumni(r, id) {
+		http.Error(w, i18n.T(r, i18n.MsgForbidden), http.StatusForbidden)
+		return
+	}
+	var p models.PrivacySettings
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, i18n.T(r, i18n.MsgInvalidInput), http.StatusBadRequest)
+		return
+	}
+	if err := h.repo.UpdatePrivacy(r.Context(), id, p); err != nil {
+		writeRepoError(w, r, err, i18n.MsgAlumniUpdateFailed)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(p)
 }
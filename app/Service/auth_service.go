@@ -1,11 +1,16 @@
 package service
 
 import (
+	"crud-app/app/i18n"
+	"crud-app/app/logger"
+	"crud-app/app/metrics"
 	"crud-app/app/models"
 	"crud-app/app/repository"
+	"crud-app/app/session"
+	"crud-app/app/tracing"
+	"crud-app/config"
 	"encoding/json"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,48 +19,52 @@ import (
 
 type AuthService struct {
 	repo repository.UserRepository
+	cfg  config.AuthConfig
 }
 
-func NewAuthService(r repository.UserRepository) *AuthService {
-	return &AuthService{repo: r}
+func NewAuthService(r repository.UserRepository, cfg config.AuthConfig) *AuthService {
+	return &AuthService{repo: r, cfg: cfg}
 }
 
 func (h *AuthService) Register(w http.ResponseWriter, r *http.Request) {
-    var u models.User
-    if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
-        http.Error(w, "Invalid input", http.StatusBadRequest)
-        return
-    }
-
-    // Validasi minimal
-    if u.Email == "" || u.Username == "" || u.Password == "" {
-        http.Error(w, "Email, Username, and Password are required", http.StatusBadRequest)
-        return
-    }
-
-    if len(u.Password) < 6 {
-        http.Error(w, "Password must be at least 6 characters", http.StatusBadRequest)
-        return
-    }
-
-    // Hash password
-    hash, _ := bcrypt.GenerateFromPassword([]byte(u.Password), 10)
-    u.Password = string(hash)
-
-    // Default role
-    if u.Role == "" {
-        u.Role = "user"
-    }
-
-    // Simpan user ke DB
-    if err := h.repo.Create(&u); err != nil {
-        http.Error(w, "User already exists or DB error", http.StatusBadRequest)
-        return
-    }
-
-    json.NewEncoder(w).Encode(map[string]string{"message": "User registered successfully"})
-}
+	ctx, span := tracing.Start(r.Context(), "AuthService.Register")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	var u models.User
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		http.Error(w, i18n.T(r, i18n.MsgInvalidInput), http.StatusBadRequest)
+		return
+	}
 
+	// Validasi minimal
+	if u.Email == "" || u.Username == "" || u.Password == "" {
+		http.Error(w, i18n.T(r, i18n.MsgRequiredCredentials), http.StatusBadRequest)
+		return
+	}
+
+	if len(u.Password) < 6 {
+		http.Error(w, i18n.T(r, i18n.MsgPasswordTooShort), http.StatusBadRequest)
+		return
+	}
+
+	// Hash password
+	hash, _ := bcrypt.GenerateFromPassword([]byte(u.Password), 10)
+	u.Password = string(hash)
+
+	// Default role
+	if u.Role == "" {
+		u.Role = "user"
+	}
+
+	// Simpan user ke DB
+	if err := h.repo.Create(r.Context(), &u); err != nil {
+		http.Error(w, i18n.T(r, i18n.MsgUserExists), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgRegistered)})
+}
 
 // Login user
 // func (h *AuthService) Login(w http.ResponseWriter, r *http.Request) {
@@ -91,32 +100,75 @@ func (h *AuthService) Register(w http.ResponseWriter, r *http.Request) {
 
 // Login user
 func (h *AuthService) Login(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "AuthService.Login")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	var req models.User
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid input", http.StatusBadRequest)
+		http.Error(w, i18n.T(r, i18n.MsgInvalidInput), http.StatusBadRequest)
 		return
 	}
 
-	user, err := h.repo.GetByUsername(req.Username)
+	user, err := h.repo.GetByUsername(r.Context(), req.Username)
 	if err != nil {
-		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+		metrics.Logins.WithLabelValues("failure").Inc()
+		logger.FromContext(r.Context()).Warn("login failed", "username", req.Username, "reason", "unknown user")
+		http.Error(w, i18n.T(r, i18n.MsgInvalidCredentials), http.StatusUnauthorized)
 		return
 	}
 
 	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
-		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+		metrics.Logins.WithLabelValues("failure").Inc()
+		logger.FromContext(r.Context()).Warn("login failed", "username", req.Username, "reason", "wrong password")
+		http.Error(w, i18n.T(r, i18n.MsgInvalidCredentials), http.StatusUnauthorized)
 		return
 	}
 
 	claims := jwt.MapClaims{
 		"sub":  user.ID,
 		"role": user.Role,
-		"exp":  time.Now().Add(time.Hour * 24).Unix(),
+		"exp":  time.Now().Add(h.cfg.TokenTTL.Duration).Unix(),
+	}
+
+	// ?mode=cookie: token disimpan di cookie HttpOnly, bukan dikembalikan
+	// ke JavaScript. Token CSRF ikut disimpan di claim agar terikat ke sesi.
+	cookieMode := r.URL.Query().Get("mode") == "cookie"
+	var csrf string
+	if cookieMode {
+		var err error
+		if csrf, err = session.NewCSRFToken(); err != nil {
+			logger.FromContext(r.Context()).Error("generate csrf token", "error", err)
+			http.Error(w, i18n.T(r, i18n.MsgInternalError), http.StatusInternalServerError)
+			return
+		}
+		claims[session.CSRFClaim] = csrf
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	secret := os.Getenv("JWT_SECRET")
-	t, _ := token.SignedString([]byte(secret))
+	t, _ := token.SignedString([]byte(h.cfg.JWTSecret))
+
+	metrics.Logins.WithLabelValues("success").Inc()
+	logger.FromContext(r.Context()).Info("login succeeded", "user_id", user.ID.Hex(), "cookie", cookieMode)
 
+	if cookieMode {
+		session.Set(w, h.sessionOptions(), t, csrf, h.cfg.TokenTTL.Duration)
+		json.NewEncoder(w).Encode(map[string]string{"csrf_token": csrf})
+		return
+	}
 	json.NewEncoder(w).Encode(map[string]string{"token": t})
-}
\ No newline at end of file
+}
+
+// Logout menghapus cookie sesi. Untuk mode Bearer cukup buang token di client.
+func (h *AuthService) Logout(w http.ResponseWriter, r *http.Request) {
+	session.Clear(w, h.sessionOptions())
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgLoggedOut)})
+}
+
+func (h *AuthService) sessionOptions() session.Options {
+	return session.Options{
+		Secure:   h.cfg.Session.CookieSecure,
+		SameSite: session.ParseSameSite(h.cfg.Session.SameSite),
+		Domain:   h.cfg.Session.Domain,
+	}
+}
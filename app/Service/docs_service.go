@@ -0,0 +1,93 @@
+package service
+
+import (
+	"crud-app/app/openapi"
+	"embed"
+	"net/http"
+)
+
+// Redoc disajikan dari bundle yang di-embed ke binary (assets/), bukan dari
+// CDN, sehingga /docs tidak bergantung pada pihak ketiga dan tetap jalan
+// tanpa internet. Bundle diambil dari npm (integritas tarball diperiksa npm)
+// dan di-commit; perbarui versi di sini lalu jalankan go generate.
+//
+//go:generate sh -c "npm pack --silent redoc@2.1.5 && tar -xzOf redoc-2.1.5.tgz package/bundles/redoc.standalone.js > assets/redoc.standalone.js && rm redoc-2.1.5.tgz"
+const (
+	redocPath = "/docs/redoc.standalone.js"
+	docsCSP   = "default-src 'none'; script-src 'self'; style-src 'unsafe-inline'; " +
+		"img-src 'self' data:; font-src data:; connect-src 'self'; worker-src blob:; frame-ancestors 'none'"
+)
+
+//go:embed assets
+var assets embed.FS
+
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Alumni API</title>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="/openapi.json"></redoc>
+  <script src="` + redocPath + `"></script>
+</body>
+</html>
+`
+
+// docsMissingPage ditampilkan jika binary di-build tanpa bundle Redoc.
+const docsMissingPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Alumni API</title>
+</head>
+<body>
+  <p>Bundle Redoc tidak ikut di-build (jalankan <code>go generate ./app/Service</code>).
+  Spesifikasi tersedia di <a href="/openapi.json">/openapi.json</a>.</p>
+</body>
+</html>
+`
+
+type DocsService struct {
+	spec  *openapi.Spec
+	redoc []byte
+}
+
+func NewDocsService(spec *openapi.Spec) *DocsService {
+	redoc, _ := assets.ReadFile("assets/redoc.standalone.js")
+	return &DocsService{spec: spec, redoc: redoc}
+}
+
+// HasRedoc melaporkan apakah bundle Redoc ikut di-build.
+func (h *DocsService) HasRedoc() bool {
+	return len(h.redoc) > 0
+}
+
+// OpenAPI menyajikan spesifikasi OpenAPI dalam JSON.
+func (h *DocsService) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(h.spec.JSON())
+}
+
+// Docs menampilkan dokumentasi interaktif (Redoc) dari /openapi.json.
+func (h *DocsService) Docs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Security-Policy", docsCSP)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if !h.HasRedoc() {
+		w.Write([]byte(docsMissingPage))
+		return
+	}
+	w.Write([]byte(docsPage))
+}
+
+// Redoc menyajikan bundle Redoc yang di-embed.
+func (h *DocsService) Redoc(w http.ResponseWriter, r *http.Request) {
+	if !h.HasRedoc() {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Write(h.redoc)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDocsLoadsOnlySameOriginScripts(t *testing.T) {
+	h := &DocsService{redoc: []byte("/* redoc */")}
+	w := httptest.NewRecorder()
+	h.Docs(w, httptest.NewRequest("GET", "/docs", nil))
+
+	csp := w.Header().Get("Content-Security-Policy")
+	if !strings.Contains(csp, "script-src 'self';") {
+		t.Errorf("CSP %q does not restrict scripts to 'self'", csp)
+	}
+	for _, external := range []string{"http:", "https:", "//"} {
+		if strings.Contains(csp, external) {
+			t.Errorf("CSP %q allows an external origin", csp)
+		}
+	}
+	if !strings.Contains(w.Body.String(), `<script src="`+redocPath+`">`) {
+		t.Errorf("docs page does not load %s:\n%s", redocPath, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "//") {
+		t.Errorf("docs page references an external URL:\n%s", w.Body.String())
+	}
+}
+
+func TestRedocBundle(t *testing.T) {
+	h := &DocsService{redoc: []byte("/* redoc */")}
+	w := httptest.NewRecorder()
+	h.Redoc(w, httptest.NewRequest("GET", redocPath, nil))
+	if w.Code != http.StatusOK || w.Body.String() != "/* redoc */" {
+		t.Errorf("Redoc = %d %q, want embedded bundle", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/javascript") {
+		t.Errorf("Content-Type = %q, want text/javascript", ct)
+	}
+
+	// tanpa bundle: /docs tidak memuat script dan bundle 404
+	missing := &DocsService{}
+	w = httptest.NewRecorder()
+	missing.Redoc(w, httptest.NewRequest("GET", redocPath, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Redoc without bundle = %d, want 404", w.Code)
+	}
+	w = httptest.NewRecorder()
+	missing.Docs(w, httptest.NewRequest("GET", "/docs", nil))
+	if strings.Contains(w.Body.String(), "<script") {
+		t.Errorf("docs page without bundle loads a script:\n%s", w.Body.String())
+	}
+}
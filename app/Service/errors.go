@@ -0,0 +1,41 @@
+package service
+
+import (
+	"crud-app/app/i18n"
+	"crud-app/app/logger"
+	"crud-app/app/repository"
+	"crud-app/app/tracing"
+	"errors"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// writeRepoError memetakan error dari repository ke status HTTP dan pesan
+// yang sudah diterjemahkan. fallback dipakai untuk error yang tidak dikenal.
+func writeRepoError(w http.ResponseWriter, r *http.Request, err error, fallback i18n.Key) {
+	switch {
+	case errors.Is(err, primitive.ErrInvalidHex):
+		http.Error(w, i18n.T(r, i18n.MsgInvalidID), http.StatusBadRequest)
+	case errors.Is(err, mongo.ErrNoDocuments):
+		http.Error(w, i18n.T(r, i18n.MsgDataNotFound), http.StatusNotFound)
+	case errors.Is(err, repository.ErrUserNotFound):
+		http.Error(w, i18n.T(r, i18n.MsgUserNotFound), http.StatusNotFound)
+	case errors.Is(err, repository.ErrAlreadyRestored):
+		http.Error(w, i18n.T(r, i18n.MsgAlreadyRestore), http.StatusNotFound)
+	case errors.Is(err, repository.ErrNothingToRestore):
+		http.Error(w, i18n.T(r, i18n.MsgNothingRestore), http.StatusNotFound)
+	case errors.Is(err, repository.ErrNotInTrash):
+		http.Error(w, i18n.T(r, i18n.MsgNotInTrash), http.StatusNotFound)
+	case errors.Is(err, repository.ErrTrashEmpty):
+		http.Error(w, i18n.T(r, i18n.MsgTrashEmpty), http.StatusNotFound)
+	case errors.Is(err, repository.ErrQueryTimeout):
+		logger.FromContext(r.Context()).Warn("query timed out", "error", err)
+		http.Error(w, i18n.T(r, i18n.MsgQueryTimeout), http.StatusGatewayTimeout)
+	default:
+		logger.FromContext(r.Context()).Error("request failed", "error", err)
+		tracing.RecordError(r.Context(), err)
+		http.Error(w, i18n.T(r, fallback), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"crud-app/app/i18n"
+	"crud-app/app/models"
+	"crud-app/app/query"
+	"encoding/json"
+	"net/http"
+)
+
+// currentRole mengembalikan role user yang login, atau "" bila tidak ada.
+func currentRole(r *http.Request) string {
+	if u, ok := r.Context().Value("user").(models.User); ok {
+		return u.Role
+	}
+	return ""
+}
+
+// parseFields membaca ?fields= terhadap allowlist role user; field yang
+// tidak dikenal atau tidak boleh dilihat dijawab 400.
+func parseFields(w http.ResponseWriter, r *http.Request, fs query.Fieldset) ([]string, bool) {
+	fields, err := fs.Parse(r.URL.Query().Get(query.FieldsParam), currentRole(r))
+	if err != nil {
+		http.Error(w, i18n.T(r, i18n.MsgInvalidParam, query.FieldsParam), http.StatusBadRequest)
+		return nil, false
+	}
+	return fields, true
+}
+
+// docPolicy menghapus field yang tidak boleh dilihat dari satu dokumen
+// JSON. nil berarti semua field boleh dilihat.
+type docPolicy func(doc map[string]json.RawMessage)
+
+// sparse mengubah v (dokumen atau slice dokumen) menjadi JSON yang hanya
+// berisi fields, ditambah keep (misalnya relasi dari include), setelah
+// policy diterapkan per dokumen. Field yang tidak diambil dari Mongo tidak
+// muncul sebagai nilai kosong.
+func sparse(v interface{}, fields []string, policy docPolicy, keep ...string) (interface{}, error) {
+	if fields == nil && policy == nil {
+		return v, nil
+	}
+	set := make(map[string]bool, len(fields)+len(keep))
+	for _, f := range append(append([]string{}, fields...), keep...) {
+		set[f] = true
+	}
+	filter := func(doc map[string]json.RawMessage) {
+		if policy != nil {
+			policy(doc)
+		}
+		if fields == nil {
+			return
+		}
+		for k := range doc {
+			if !set[k] {
+				delete(doc, k)
+			}
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > 0 && b[0] == '[' {
+		var docs []map[string]json.RawMessage
+		if err := json.Unmarshal(b, &docs); err != nil {
+			return nil, err
+		}
+		for _, d := range docs {
+			filter(d)
+		}
+		return docs, nil
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	filter(doc)
+	return doc, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HealthCheck adalah satu dependency yang diperiksa oleh /readyz.
+// Details boleh nil; jika diisi, ikut ditampilkan di response.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) (details interface{}, err error)
+}
+
+type checkResult struct {
+	Status    string      `json:"status"`
+	LatencyMS int64       `json:"latency_ms"`
+	Error     string      `json:"error,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+type HealthService struct {
+	checks  []HealthCheck
+	timeout time.Duration
+}
+
+func NewHealthService(timeout time.Duration, checks ...HealthCheck) *HealthService {
+	return &HealthService{checks: checks, timeout: timeout}
+}
+
+// Liveness: proses hidup dan bisa melayani HTTP. Tidak memeriksa dependency
+// agar orchestrator tidak me-restart service hanya karena Mongo mati.
+func (h *HealthService) Liveness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// Readiness: semua dependency siap. 503 jika ada yang belum.
+func (h *HealthService) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	ready := true
+	results := make(map[string]checkResult, len(h.checks))
+	for _, c := range h.checks {
+		start := time.Now()
+		details, err := c.Check(ctx)
+
+		res := checkResult{Status: "up", LatencyMS: time.Since(start).Milliseconds(), Details: details}
+		if err != nil {
+			ready = false
+			res.Status, res.Error = "down", err.Error()
+		}
+		results[c.Name] = res
+	}
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "not_ready", http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": status,
+		"checks": results,
+	})
+}
@@ -1,11 +1,13 @@
 package service
 
 import (
+	"crud-app/app/i18n"
+	"crud-app/app/metrics"
 	"crud-app/app/models"
 	"crud-app/app/repository"
+	"crud-app/app/tracing"
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/gorilla/mux"
 )
@@ -19,53 +21,78 @@ func NewPekerjaanService(r repository.PekerjaanRepository) *PekerjaanService {
 }
 
 func (h *PekerjaanService) GetByAlumni(w http.ResponseWriter, r *http.Request) {
-	alumniID := mux.Vars(r)["alumni_id"]
-	data, err := h.repo.FindByAlumni(alumniID)
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.GetByAlumni")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	alumniID := mux.Vars(r)["id"]
+	data, err := h.repo.FindByAlumni(r.Context(), alumniID)
 	if err != nil {
-		http.Error(w, "Not found", http.StatusNotFound)
+		http.Error(w, i18n.T(r, i18n.MsgPekerjaanNotFound), http.StatusNotFound)
 		return
 	}
 	json.NewEncoder(w).Encode(data)
 }
 
 func (h *PekerjaanService) Create(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.Create")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	var p models.Pekerjaan
 	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
-		http.Error(w, "Invalid input", http.StatusBadRequest)
+		http.Error(w, i18n.T(r, i18n.MsgInvalidInput), http.StatusBadRequest)
 		return
 	}
-	if err := h.repo.Create(&p); err != nil {
-		http.Error(w, "Failed to create pekerjaan", http.StatusInternalServerError)
+	if err := h.repo.Create(r.Context(), &p); err != nil {
+		writeRepoError(w, r, err, i18n.MsgPekerjaanCreateFailed)
 		return
 	}
 	json.NewEncoder(w).Encode(p)
 }
 
 func (h *PekerjaanService) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.GetByID")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	id := mux.Vars(r)["id"]
+	fields, ok := parseFields(w, r, repository.PekerjaanFields)
+	if !ok {
+		return
+	}
 
-	pekerjaan, err := h.repo.FindByPekerjaanID(id)
+	pekerjaan, err := h.repo.FindByPekerjaanID(r.Context(), id, fields)
 	if err != nil {
-		http.Error(w, "Data not found", http.StatusNotFound)
+		http.Error(w, i18n.T(r, i18n.MsgPekerjaanNotFound), http.StatusNotFound)
+		return
+	}
+	body, err := sparse(pekerjaan, fields, nil)
+	if err != nil {
+		writeRepoError(w, r, err, i18n.MsgInternalError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(pekerjaan)
+	json.NewEncoder(w).Encode(body)
 }
 
 func (h *PekerjaanService) Update(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.Update")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	id := mux.Vars(r)["id"]
 	var p models.Pekerjaan
 	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
-		http.Error(w, "Invalid input", http.StatusBadRequest)
+		http.Error(w, i18n.T(r, i18n.MsgInvalidInput), http.StatusBadRequest)
 		return
 	}
-	if err := h.repo.Update(id, &p); err != nil {
-		http.Error(w, "Failed to update pekerjaan", http.StatusInternalServerError)
+	if err := h.repo.Update(r.Context(), id, &p); err != nil {
+		writeRepoError(w, r, err, i18n.MsgPekerjaanUpdateFailed)
 		return
 	}
-	json.NewEncoder(w).Encode(map[string]string{"message": "Updated successfully"})
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgUpdated)})
 }
 
 // func (h *PekerjaanService) Delete(w http.ResponseWriter, r *http.Request) {
@@ -85,59 +112,57 @@ func (h *PekerjaanService) Update(w http.ResponseWriter, r *http.Request) {
 // 	}
 // 	json.NewEncoder(w).Encode(pekerjaan)
 // }
- 
 
 func (h *PekerjaanService) GetPekerjaan(w http.ResponseWriter, r *http.Request) {
-	q := r.URL.Query()
-	page, _ := strconv.Atoi(q.Get("page"))
-	if page < 1 {
-		page = 1
-	}
-	limit, _ := strconv.Atoi(q.Get("limit"))
-	if limit < 1 {
-		limit = 10
-	}
-	search := q.Get("search")
-	sortBy := q.Get("sortBy")
-	if sortBy == "" {
-		sortBy = "id"
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.GetPekerjaan")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	q, ok := parseQuery(w, r, repository.PekerjaanSchema)
+	if !ok {
+		return
 	}
-	order := q.Get("order")
-	if order == "" {
-		order = "asc"
+	if q.Fields, ok = parseFields(w, r, repository.PekerjaanFields); !ok {
+		return
 	}
 
-	data, total, err := h.repo.GetPekerjaan(search, sortBy, order, page, limit)
+	list, page, err := h.repo.GetPekerjaan(r.Context(), q)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeRepoError(w, r, err, i18n.MsgInternalError)
+		return
+	}
+	data, err := sparse(list, q.Fields, nil)
+	if err != nil {
+		writeRepoError(w, r, err, i18n.MsgInternalError)
 		return
 	}
 
 	resp := map[string]interface{}{
 		"data": data,
-		"meta": map[string]interface{}{
-			"page":   page,
-			"limit":  limit,
-			"total":  total,
-			"pages":  (total + limit - 1) / limit,
-			"sortBy": sortBy,
-			"order":  order,
-			"search": search,
-		},
+		"meta": listMeta(w, r, q, page),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	_, encodeSpan := tracing.Start(ctx, "json.Encode")
 	json.NewEncoder(w).Encode(resp)
+	encodeSpan.End()
 }
 
-
+// SoftDeletePekerjaan adalah perilaku lama DELETE /pekerjaan/{id}: admin
+// menghapus semua pekerjaan alumni ?alumni_id, user menghapus pekerjaan {id}
+// miliknya. Hanya dipakai alias legacy; /api/v1 memakai SoftDelete dan
+// SoftDeleteByAlumni.
 func (s *PekerjaanService) SoftDeletePekerjaan(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.SoftDeletePekerjaan")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	vars := mux.Vars(r)
 	pekerjaanID := vars["id"]
 
 	userVal := r.Context().Value("user")
 	if userVal == nil {
-		http.Error(w, "Unauthorized: user not found in context", http.StatusUnauthorized)
+		http.Error(w, i18n.T(r, i18n.MsgUserNotInCtx), http.StatusUnauthorized)
 		return
 	}
 
@@ -146,109 +171,108 @@ func (s *PekerjaanService) SoftDeletePekerjaan(w http.ResponseWriter, r *http.Re
 	if user.Role == "admin" {
 		alumniID := r.URL.Query().Get("alumni_id")
 
-		if err := s.repo.SoftDeleteByAdmin(alumniID); err != nil {
-			http.Error(w, "Failed to soft delete pekerjaan (admin)", http.StatusInternalServerError)
+		if err := s.repo.SoftDeleteByAdmin(r.Context(), alumniID); err != nil {
+			writeRepoError(w, r, err, i18n.MsgPekerjaanAdminDeleteFailed)
 			return
 		}
-		json.NewEncoder(w).Encode(map[string]string{"message": "Semua riwayat pekerjaan alumni berhasil dihapus"})
+		metrics.SoftDeletes.WithLabelValues("pekerjaan", "alumni").Inc()
+		json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgPekerjaanAllSoftDeleted)})
 		return
 	}
 
 	userIDStr := user.ID.Hex()
-	if err := s.repo.SoftDeleteByUser(pekerjaanID, userIDStr); err != nil {
-		http.Error(w, "Failed to soft delete pekerjaan (user)", http.StatusInternalServerError)
+	if err := s.repo.SoftDeleteByUser(r.Context(), pekerjaanID, userIDStr); err != nil {
+		writeRepoError(w, r, err, i18n.MsgPekerjaanUserDeleteFailed)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"message": "Pekerjaan berhasil dihapus"})
+	metrics.SoftDeletes.WithLabelValues("pekerjaan", "single").Inc()
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgPekerjaanSoftDeleted)})
 }
 
 // GetTrash - Get semua data yang sudah di-soft delete
 func (h *PekerjaanService) GetTrash(w http.ResponseWriter, r *http.Request) {
-	q := r.URL.Query()
-	page, _ := strconv.Atoi(q.Get("page"))
-	if page < 1 {
-		page = 1
-	}
-	limit, _ := strconv.Atoi(q.Get("limit"))
-	if limit < 1 {
-		limit = 10
-	}
-	search := q.Get("search")
-	sortBy := q.Get("sortBy")
-	if sortBy == "" {
-		sortBy = "is_delete"
-	}
-	order := q.Get("order")
-	if order == "" {
-		order = "desc"
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.GetTrash")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	q, ok := parseQuery(w, r, repository.TrashSchema)
+	if !ok {
+		return
 	}
 
-	data, total, err := h.repo.GetTrash(search, sortBy, order, page, limit)
+	data, page, err := h.repo.GetTrash(r.Context(), q)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeRepoError(w, r, err, i18n.MsgInternalError)
 		return
 	}
 
 	resp := map[string]interface{}{
 		"data": data,
-		"meta": map[string]interface{}{
-			"page":   page,
-			"limit":  limit,
-			"total":  total,
-			"pages":  (total + limit - 1) / limit,
-			"sortBy": sortBy,
-			"order":  order,
-			"search": search,
-		},
+		"meta": listMeta(w, r, q, page),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	_, encodeSpan := tracing.Start(ctx, "json.Encode")
 	json.NewEncoder(w).Encode(resp)
+	encodeSpan.End()
 }
 
-// RestorePekerjaan - Restore data dari trash
+// RestorePekerjaan - Restore data dari trash (perilaku lama, hanya untuk
+// alias legacy; /api/v1 memakai Restore dan RestoreByAlumni)
 func (h *PekerjaanService) RestorePekerjaan(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.RestorePekerjaan")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	vars := mux.Vars(r)
 	pekerjaanID := vars["id"]
 
 	userVal := r.Context().Value("user")
 	if userVal == nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		http.Error(w, i18n.T(r, i18n.MsgUnauthorized), http.StatusUnauthorized)
 		return
 	}
 	user := userVal.(models.User)
 
 	var err error
+	scope := "single"
 
 	if user.Role == "admin" {
 		alumniIDStr := r.URL.Query().Get("alumni_id")
 		if alumniIDStr != "" {
-			err = h.repo.RestoreByAdmin(alumniIDStr)
+			scope = "alumni"
+			err = h.repo.RestoreByAdmin(r.Context(), alumniIDStr)
 		} else {
-			err = h.repo.Restore(pekerjaanID, "")
+			err = h.repo.Restore(r.Context(), pekerjaanID, "")
 		}
 	} else {
 		userIDStr := user.ID.Hex()
-		err = h.repo.Restore(pekerjaanID, userIDStr)
+		err = h.repo.Restore(r.Context(), pekerjaanID, userIDStr)
 	}
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeRepoError(w, r, err, i18n.MsgInternalError)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"message": "Data restored successfully"})
+	metrics.Restores.WithLabelValues("pekerjaan", scope).Inc()
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgRestored)})
 }
 
-// HardDeletePekerjaan - Hapus permanen data dari trash
+// HardDeletePekerjaan - Hapus permanen data dari trash (perilaku lama, hanya
+// untuk alias legacy; /api/v1 memakai HardDelete dan HardDeleteByAlumni)
 func (h *PekerjaanService) HardDeletePekerjaan(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.HardDeletePekerjaan")
+	defer span.End()
+	r = r.WithContext(ctx)
+
 	vars := mux.Vars(r)
 	pekerjaanID := vars["id"]
 
 	userVal := r.Context().Value("user")
 	if userVal == nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		http.Error(w, i18n.T(r, i18n.MsgUnauthorized), http.StatusUnauthorized)
 		return
 	}
 	user := userVal.(models.User)
@@ -258,19 +282,137 @@ func (h *PekerjaanService) HardDeletePekerjaan(w http.ResponseWriter, r *http.Re
 	if user.Role == "admin" {
 		alumniIDStr := r.URL.Query().Get("alumni_id")
 		if alumniIDStr != "" {
-			err = h.repo.HardDeleteByAdmin(alumniIDStr)
+			err = h.repo.HardDeleteByAdmin(r.Context(), alumniIDStr)
 		} else {
-			err = h.repo.HardDelete(pekerjaanID, "")
+			err = h.repo.HardDelete(r.Context(), pekerjaanID, "")
 		}
 	} else {
 		userIDStr := user.ID.Hex()
-		err = h.repo.HardDelete(pekerjaanID, userIDStr)
+		err = h.repo.HardDelete(r.Context(), pekerjaanID, userIDStr)
 	}
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeRepoError(w, r, err, i18n.MsgInternalError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgHardDeleted)})
+}
+
+// SoftDelete memindahkan pekerjaan {id} ke sampah. Admin boleh menghapus
+// pekerjaan siapa saja, user hanya miliknya sendiri.
+func (h *PekerjaanService) SoftDelete(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.SoftDelete")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	owner, ok := ownerScope(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.SoftDelete(r.Context(), mux.Vars(r)["id"], owner); err != nil {
+		writeRepoError(w, r, err, i18n.MsgPekerjaanUserDeleteFailed)
+		return
+	}
+
+	metrics.SoftDeletes.WithLabelValues("pekerjaan", "single").Inc()
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgPekerjaanSoftDeleted)})
+}
+
+// SoftDeleteByAlumni memindahkan semua pekerjaan alumni {id} ke sampah (admin).
+func (h *PekerjaanService) SoftDeleteByAlumni(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.SoftDeleteByAlumni")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	if err := h.repo.SoftDeleteByAdmin(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeRepoError(w, r, err, i18n.MsgPekerjaanAdminDeleteFailed)
+		return
+	}
+
+	metrics.SoftDeletes.WithLabelValues("pekerjaan", "alumni").Inc()
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgPekerjaanAllSoftDeleted)})
+}
+
+// Restore memulihkan pekerjaan {id} dari sampah (admin: siapa saja, user: miliknya).
+func (h *PekerjaanService) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.Restore")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	owner, ok := ownerScope(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.Restore(r.Context(), mux.Vars(r)["id"], owner); err != nil {
+		writeRepoError(w, r, err, i18n.MsgInternalError)
+		return
+	}
+
+	metrics.Restores.WithLabelValues("pekerjaan", "single").Inc()
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgRestored)})
+}
+
+// RestoreByAlumni memulihkan semua pekerjaan alumni {id} dari sampah (admin).
+func (h *PekerjaanService) RestoreByAlumni(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.RestoreByAlumni")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	if err := h.repo.RestoreByAdmin(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeRepoError(w, r, err, i18n.MsgInternalError)
+		return
+	}
+
+	metrics.Restores.WithLabelValues("pekerjaan", "alumni").Inc()
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgRestored)})
+}
+
+// HardDelete menghapus permanen pekerjaan {id} yang ada di sampah
+// (admin: siapa saja, user: miliknya).
+func (h *PekerjaanService) HardDelete(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.HardDelete")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	owner, ok := ownerScope(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.HardDelete(r.Context(), mux.Vars(r)["id"], owner); err != nil {
+		writeRepoError(w, r, err, i18n.MsgInternalError)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"message": "Data permanently deleted"})
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgHardDeleted)})
+}
+
+// HardDeleteByAlumni menghapus permanen semua pekerjaan alumni {id} yang ada
+// di sampah (admin).
+func (h *PekerjaanService) HardDeleteByAlumni(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "PekerjaanService.HardDeleteByAlumni")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	if err := h.repo.HardDeleteByAdmin(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeRepoError(w, r, err, i18n.MsgInternalError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r, i18n.MsgHardDeleted)})
+}
+
+// ownerScope mengembalikan ID pemilik untuk filter repository: kosong untuk
+// admin (tanpa batasan), ID user untuk role lain.
+func ownerScope(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userVal := r.Context().Value("user")
+	if userVal == nil {
+		http.Error(w, i18n.T(r, i18n.MsgUserNotInCtx), http.StatusUnauthorized)
+		return "", false
+	}
+	user := userVal.(models.User)
+	if user.Role == "admin" {
+		return "", true
+	}
+	return user.ID.Hex(), true
 }
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"crud-app/app/i18n"
+	"crud-app/app/models"
+	"crud-app/app/query"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Nilai parameter include yang dikenal.
+const includePekerjaan = "pekerjaan"
+
+// parseInclude membaca ?include=a,b dan menolak nilai di luar allowed dengan 400.
+func parseInclude(w http.ResponseWriter, r *http.Request, allowed ...string) (map[string]bool, bool) {
+	include := map[string]bool{}
+	for _, v := range strings.Split(r.URL.Query().Get("include"), ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		known := false
+		for _, a := range allowed {
+			if v == a {
+				known = true
+				break
+			}
+		}
+		if !known {
+			http.Error(w, i18n.T(r, i18n.MsgInvalidInclude, v), http.StatusBadRequest)
+			return nil, false
+		}
+		include[v] = true
+	}
+	return include, true
+}
+
+// includedRelations mengembalikan key JSON yang ditambahkan include, agar
+// tidak dibuang oleh sparse.
+func includedRelations(include map[string]bool) []string {
+	if include[includePekerjaan] {
+		return []string{"pekerjaan", "current_job"}
+	}
+	return nil
+}
+
+// parseQuery membaca parameter listing menurut schema; parameter filter
+// yang tidak valid dijawab 400.
+func parseQuery(w http.ResponseWriter, r *http.Request, schema *query.Schema) (query.Query, bool) {
+	q, err := query.Parse(r.URL.Query(), schema)
+	var qerr *query.Error
+	if errors.As(err, &qerr) {
+		switch qerr.Param {
+		case query.SearchParam, query.SearchModeParam:
+			http.Error(w, i18n.T(r, i18n.MsgInvalidSearch, query.MaxSearchLength), http.StatusBadRequest)
+		case query.AfterParam, query.BeforeParam:
+			http.Error(w, i18n.T(r, i18n.MsgInvalidCursor), http.StatusBadRequest)
+		case "page", "limit", "sortBy", "order", query.CountParam:
+			http.Error(w, i18n.T(r, i18n.MsgInvalidParam, qerr.Param), http.StatusBadRequest)
+		default:
+			http.Error(w, i18n.T(r, i18n.MsgInvalidFilter, qerr.Param), http.StatusBadRequest)
+		}
+		return q, false
+	}
+	return q, true
+}
+
+// listMeta menyusun MetaInfo untuk response listing dan memasang header
+// Link (RFC 8288) ke halaman berikutnya/sebelumnya.
+func listMeta(w http.ResponseWriter, r *http.Request, q query.Query, page query.Page) models.MetaInfo {
+	meta := models.MetaInfo{
+		Page:       q.Page,
+		Limit:      q.Limit,
+		SortBy:     q.SortBy,
+		Order:      q.Order,
+		Search:     q.Search,
+		SearchMode: string(q.SearchMode),
+		Filters:    q.Filters(),
+	}
+	if page.Total >= 0 {
+		total, pages := page.Total, (page.Total+q.Limit-1)/q.Limit
+		meta.Total = &total
+		if q.Cursor == nil {
+			meta.Pages = &pages
+		}
+	}
+
+	// link memakai URL request dengan parameter paging diganti
+	link := func(param, value string) string {
+		v := r.URL.Query()
+		v.Del("page")
+		v.Del(query.AfterParam)
+		v.Del(query.BeforeParam)
+		v.Set(param, value)
+		return r.URL.Path + "?" + v.Encode()
+	}
+	switch {
+	case q.Cursor != nil:
+		if page.HasNext {
+			meta.Next = link(query.AfterParam, page.Next)
+			meta.NextCursor = &page.Next
+		}
+		if page.HasPrev {
+			meta.Prev = link(query.BeforeParam, page.Prev)
+			meta.PrevCursor = &page.Prev
+		}
+	default:
+		if page.HasNext {
+			meta.Next = link("page", strconv.Itoa(q.Page+1))
+		}
+		if page.HasPrev {
+			meta.Prev = link("page", strconv.Itoa(q.Page-1))
+		}
+	}
+
+	var links []string
+	if meta.Next != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, meta.Next))
+	}
+	if meta.Prev != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, meta.Prev))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+	return meta
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"crud-app/app/query"
+	"crud-app/app/repository"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseQueryRejectsInjectedCursor(t *testing.T) {
+	b, err := bson.Marshal(bson.D{
+		{Key: "s", Value: "nama"},
+		{Key: "o", Value: "asc"},
+		{Key: "v", Value: bson.D{{Key: "$ne", Value: nil}}},
+		{Key: "i", Value: primitive.NewObjectID()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	for _, param := range []string{query.AfterParam, query.BeforeParam} {
+		v := url.Values{"sortBy": {"nama"}, param: {token}}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/v1/alumni?"+v.Encode(), nil)
+		if _, ok := parseQuery(w, r, repository.AlumniSchema); ok {
+			t.Fatalf("%s: crafted cursor accepted", param)
+		}
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", param, w.Code)
+		}
+	}
+}
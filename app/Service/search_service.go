@@ -0,0 +1,160 @@
+package service
+
+import (
+	"crud-app/app/i18n"
+	"crud-app/app/models"
+	"crud-app/app/query"
+	"crud-app/app/repository"
+	"crud-app/app/search"
+	"crud-app/app/tracing"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	searchDefaultLimit = 10
+	searchMaxLimit     = 50
+	// searchMaxDepth membatasi page*limit: setiap koleksi mengambil
+	// skip+limit hit teratas sebelum digabung.
+	searchMaxDepth = 1000
+)
+
+type SearchService struct {
+	repo repository.SearchRepository
+}
+
+func NewSearchService(r repository.SearchRepository) *SearchService {
+	return &SearchService{repo: r}
+}
+
+// Search menjawab GET /search?q=&type=: hit alumni dan pekerjaan, terurut
+// relevansi, lengkap dengan highlight.
+func (h *SearchService) Search(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(r.Context(), "SearchService.Search")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	v := r.URL.Query()
+	q := strings.TrimSpace(v.Get("q"))
+	if q == "" || utf8.RuneCountInString(q) > query.MaxSearchLength {
+		http.Error(w, i18n.T(r, i18n.MsgInvalidQuery, query.MaxSearchLength), http.StatusBadRequest)
+		return
+	}
+	kinds, ok := parseSearchKinds(w, r)
+	if !ok {
+		return
+	}
+
+	page, limit := 1, searchDefaultLimit
+	if s := v.Get("page"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			http.Error(w, i18n.T(r, i18n.MsgInvalidParam, "page"), http.StatusBadRequest)
+			return
+		}
+		page = n
+	}
+	if s := v.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			http.Error(w, i18n.T(r, i18n.MsgInvalidParam, "limit"), http.StatusBadRequest)
+			return
+		}
+		limit = min(n, searchMaxLimit)
+	}
+	if page*limit > searchMaxDepth {
+		http.Error(w, i18n.T(r, i18n.MsgInvalidParam, "page"), http.StatusBadRequest)
+		return
+	}
+
+	hits, total := []models.SearchHit{}, 0
+	// q yang hanya berisi stopword tidak punya term untuk dicari
+	if terms := search.Terms(q); len(terms) > 0 {
+		var err error
+		hits, total, err = h.repo.Search(r.Context(), terms, kinds, (page-1)*limit, limit)
+		if err != nil {
+			writeRepoError(w, r, err, i18n.MsgInternalError)
+			return
+		}
+		set := search.TermSet(terms)
+		policy := alumniPolicy(r)
+		for i := range hits {
+			decorateHit(&hits[i], set)
+			if hits[i].Type != repository.SearchAlumni {
+				continue
+			}
+			// kontak alumni mengikuti kebijakan visibilitas yang sama dengan listing
+			if hits[i].Data, err = sparse(hits[i].Data, nil, policy); err != nil {
+				writeRepoError(w, r, err, i18n.MsgInternalError)
+				return
+			}
+		}
+	}
+
+	pages := (total + limit - 1) / limit
+	response := models.SearchResponse{
+		Data: hits,
+		Meta: models.MetaInfo{
+			Page:    page,
+			Limit:   limit,
+			Total:   &total,
+			Pages:   &pages,
+			SortBy:  "score",
+			Order:   "desc",
+			Search:  q,
+			Filters: map[string]interface{}{"type": kinds},
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseSearchKinds membaca ?type=alumni,pekerjaan; kosong berarti semua.
+func parseSearchKinds(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	seen := map[string]bool{}
+	var kinds []string
+	for _, v := range strings.Split(r.URL.Query().Get("type"), ",") {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		if v != repository.SearchAlumni && v != repository.SearchPekerjaan {
+			http.Error(w, i18n.T(r, i18n.MsgInvalidFilter, "type"), http.StatusBadRequest)
+			return nil, false
+		}
+		seen[v] = true
+		kinds = append(kinds, v)
+	}
+	if len(kinds) == 0 {
+		kinds = repository.SearchKinds
+	}
+	return kinds, true
+}
+
+// decorateHit mengisi Title dan Highlights dari field yang diindeks.
+func decorateHit(hit *models.SearchHit, terms map[string]bool) {
+	var fields map[string]string
+	switch d := hit.Data.(type) {
+	case models.Alumni:
+		hit.Title = d.Nama
+		fields = map[string]string{"nama": d.Nama, "nim": d.NIM, "jurusan": d.Jurusan}
+	case models.Pekerjaan:
+		hit.Title = d.Posisi_jabatan + " - " + d.Nama_Perusahaan
+		fields = map[string]string{
+			"nama_perusahaan":     d.Nama_Perusahaan,
+			"posisi_jabatan":      d.Posisi_jabatan,
+			"bidang_industri":     d.Bidang_industri,
+			"lokasi_kerja":        d.Lokasi_kerja,
+			"deskripsi_pekerjaan": d.Deskripsi,
+		}
+	}
+	hit.Highlights = map[string]string{}
+	for name, text := range fields {
+		if s, ok := search.Highlight(text, terms); ok {
+			hit.Highlights[name] = s
+		}
+	}
+}
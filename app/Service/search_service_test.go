@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"crud-app/app/models"
+	"crud-app/app/query"
+	"crud-app/app/repository"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// searchRepoStub mengembalikan hits dan mencatat argumen Search.
+type searchRepoStub struct {
+	repository.SearchRepository
+	hits   []models.SearchHit
+	calls  int
+	terms  []string
+	kinds  []string
+	skip   int
+	limitN int
+}
+
+func (s *searchRepoStub) Search(_ context.Context, terms, kinds []string, skip, limit int) ([]models.SearchHit, int, error) {
+	s.calls++
+	s.terms, s.kinds, s.skip, s.limitN = terms, kinds, skip, limit
+	return s.hits, len(s.hits), nil
+}
+
+func doSearch(t *testing.T, repo *searchRepoStub, params url.Values, user *models.User) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest("GET", "/api/v1/search?"+params.Encode(), nil)
+	if user != nil {
+		r = r.WithContext(context.WithValue(r.Context(), "user", *user))
+	}
+	w := httptest.NewRecorder()
+	NewSearchService(repo).Search(w, r)
+	return w
+}
+
+func TestSearchRejectsInvalidParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params url.Values
+	}{
+		{"missing q", url.Values{}},
+		{"blank q", url.Values{"q": {"   "}}},
+		{"q too long", url.Values{"q": {strings.Repeat("a", query.MaxSearchLength+1)}}},
+		{"unknown type", url.Values{"q": {"budi"}, "type": {"alumni,user"}}},
+		{"page zero", url.Values{"q": {"budi"}, "page": {"0"}}},
+		{"bad limit", url.Values{"q": {"budi"}, "limit": {"abc"}}},
+		{"too deep", url.Values{"q": {"budi"}, "page": {"101"}, "limit": {"10"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &searchRepoStub{}
+			if w := doSearch(t, repo, tt.params, nil); w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", w.Code)
+			}
+			if repo.calls != 0 {
+				t.Error("repository called for invalid request")
+			}
+		})
+	}
+}
+
+func TestSearchPassesAnalyzedTerms(t *testing.T) {
+	repo := &searchRepoStub{}
+	w := doSearch(t, repo, url.Values{"q": {"Pendidikan dan Café"}, "type": {"pekerjaan,pekerjaan"}, "page": {"3"}, "limit": {"500"}}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body)
+	}
+	if !reflect.DeepEqual(repo.terms, []string{"didik", "cafe"}) {
+		t.Errorf("terms = %q, want [didik cafe]", repo.terms)
+	}
+	if !reflect.DeepEqual(repo.kinds, []string{repository.SearchPekerjaan}) {
+		t.Errorf("kinds = %q, want [pekerjaan]", repo.kinds)
+	}
+	if repo.skip != 2*searchMaxLimit || repo.limitN != searchMaxLimit {
+		t.Errorf("skip, limit = %d, %d; want %d, %d", repo.skip, repo.limitN, 2*searchMaxLimit, searchMaxLimit)
+	}
+}
+
+func TestSearchStopwordsOnly(t *testing.T) {
+	repo := &searchRepoStub{}
+	w := doSearch(t, repo, url.Values{"q": {"dan yang di"}}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	if repo.calls != 0 {
+		t.Error("repository called without search terms")
+	}
+	var res models.SearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != 0 || res.Meta.Total == nil || *res.Meta.Total != 0 {
+		t.Errorf("response = %+v, want no hits", res)
+	}
+}
+
+func TestSearchHighlightsAndVisibility(t *testing.T) {
+	alumni := models.Alumni{
+		ID:      primitive.NewObjectID(),
+		Nama:    `<script>alert("x")</script> Budi`,
+		Jurusan: "Informatika",
+		Email:   "budi@example.com",
+		Alamat:  "Jl. Mawar",
+		Privacy: models.PrivacySettings{ShowAlamat: true},
+	}
+	pekerjaan := models.Pekerjaan{Nama_Perusahaan: "PT <b>Budi</b>", Posisi_jabatan: "Engineer"}
+	// service mengubah Data hit, jadi setiap request mendapat slice baru
+	hits := func() []models.SearchHit {
+		return []models.SearchHit{
+			{Type: repository.SearchAlumni, ID: alumni.ID, Data: alumni},
+			{Type: repository.SearchPekerjaan, Data: pekerjaan},
+		}
+	}
+
+	decode := func(w *httptest.ResponseRecorder) []map[string]interface{} {
+		t.Helper()
+		var res struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Data) != 2 {
+			t.Fatalf("hits = %d, want 2", len(res.Data))
+		}
+		return res.Data
+	}
+
+	data := decode(doSearch(t, &searchRepoStub{hits: hits()}, url.Values{"q": {"budi"}}, &models.User{ID: primitive.NewObjectID(), Role: "user"}))
+	hl := data[0]["highlights"].(map[string]interface{})
+	if got, want := hl["nama"], `&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <em>Budi</em>`; got != want {
+		t.Errorf("alumni highlight = %q, want %q", got, want)
+	}
+	if got, want := data[1]["highlights"].(map[string]interface{})["nama_perusahaan"], "PT &lt;b&gt;<em>Budi</em>&lt;/b&gt;"; got != want {
+		t.Errorf("pekerjaan highlight = %q, want %q", got, want)
+	}
+	if got := data[1]["title"]; got != "Engineer - PT <b>Budi</b>" {
+		t.Errorf("title = %q", got)
+	}
+
+	doc := data[0]["data"].(map[string]interface{})
+	if _, ok := doc["email"]; ok {
+		t.Error("non-admin search hit exposes a hidden email")
+	}
+	if _, ok := doc["privacy"]; ok {
+		t.Error("non-admin search hit exposes privacy settings")
+	}
+	if doc["alamat"] != "Jl. Mawar" {
+		t.Errorf("published alamat = %v, want Jl. Mawar", doc["alamat"])
+	}
+
+	data = decode(doSearch(t, &searchRepoStub{hits: hits()}, url.Values{"q": {"budi"}}, &models.User{Role: "admin"}))
+	if data[0]["data"].(map[string]interface{})["email"] != "budi@example.com" {
+		t.Error("admin search hit hides email")
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"bytes"
+	"crud-app/app/models"
+	"crud-app/app/query"
+	"crud-app/app/repository"
+	"encoding/json"
+	"net/http"
+)
+
+// alumniPolicy adalah kebijakan visibilitas data alumni untuk user yang
+// login: admin dan alumni pemilik data melihat semua field, user lain hanya
+// kontak yang dipublikasikan lewat privacy (dan tidak melihat privacy
+// itu sendiri). Dipakai listing, detail dan search.
+func alumniPolicy(r *http.Request) docPolicy {
+	u, _ := r.Context().Value("user").(models.User)
+	if u.Role == "admin" {
+		return nil
+	}
+	self, _ := json.Marshal(u.ID)
+	return func(doc map[string]json.RawMessage) {
+		if !u.ID.IsZero() && bytes.Equal(doc["id"], self) {
+			return
+		}
+		var p models.PrivacySettings
+		if raw, ok := doc["privacy"]; ok {
+			json.Unmarshal(raw, &p)
+		}
+		delete(doc, "privacy")
+		for field, show := range p.Published() {
+			if !show {
+				delete(doc, field)
+			}
+		}
+	}
+}
+
+// alumniSchema memilih skema listing alumni sesuai role.
+func alumniSchema(r *http.Request) *query.Schema {
+	if currentRole(r) == "admin" {
+		return repository.AlumniSchema
+	}
+	return repository.AlumniPublicSchema
+}
+
+// withPrivacy menambahkan privacy ke field yang diambil dari Mongo karena
+// alumniPolicy membutuhkannya, tanpa mengubah field yang dikirim.
+func withPrivacy(fields []string) []string {
+	for _, f := range fields {
+		if f == "privacy" {
+			return fields
+		}
+	}
+	return append(append([]string{}, fields...), "privacy")
+}
+
+// canManageAlumni melaporkan apakah user yang login boleh mengelola data
+// alumni id: admin, atau alumni itu sendiri (ID user sama dengan ID alumni).
+func canManageAlumni(r *http.Request, id string) bool {
+	u, ok := r.Context().Value("user").(models.User)
+	return ok && (u.Role == "admin" || u.ID.Hex() == id)
+}
@@ -0,0 +1,213 @@
+// Package fieldcrypt mengenkripsi field dokumen at rest dengan envelope
+// encryption: setiap dokumen punya data key (DEK) AES-256-GCM sendiri yang
+// dibungkus oleh key encryption key (KEK) dari konfigurasi. Rotasi KEK
+// cukup membungkus ulang atau membuat DEK baru per dokumen, dan KEK lama
+// tetap bisa membuka dokumen yang belum dirotasi.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// KeySize adalah panjang KEK, DEK dan blind index key dalam byte.
+const KeySize = 32
+
+// prefix menandai nilai terenkripsi. Nilai tanpa prefix dianggap plaintext
+// lama yang belum dirotasi.
+const prefix = "enc:v1:"
+
+var (
+	ErrUnknownKey = errors.New("fieldcrypt: unknown key id")
+	ErrDecrypt    = errors.New("fieldcrypt: cannot decrypt value")
+)
+
+// Keyring menyimpan KEK per key id, key id aktif untuk DEK baru dan key
+// blind index.
+type Keyring struct {
+	keks   map[string]cipher.AEAD
+	active string
+	index  []byte
+}
+
+// ParseKeyring membangun Keyring dari key base64 (32 byte) per key id.
+// active harus ada di keys; indexKey dipakai untuk blind index dan tidak
+// ikut dirotasi karena mengubahnya berarti menghitung ulang semua index.
+func ParseKeyring(keys map[string]string, active, indexKey string) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no keys configured")
+	}
+	k := &Keyring{keks: make(map[string]cipher.AEAD, len(keys)), active: active}
+	for _, id := range sortedIDs(keys) {
+		if id == "" || strings.ContainsAny(id, ":,") {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		raw, err := decodeKey(keys[id])
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		if k.keks[id], err = newAEAD(raw); err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+	}
+	if _, ok := k.keks[active]; !ok {
+		return nil, fmt.Errorf("active key %q is not configured", active)
+	}
+	var err error
+	if k.index, err = decodeKey(indexKey); err != nil {
+		return nil, fmt.Errorf("blind index key: %w", err)
+	}
+	return k, nil
+}
+
+func decodeKey(s string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, errors.New("must be base64")
+	}
+	if len(raw) != KeySize {
+		return nil, fmt.Errorf("must be %d bytes, got %d", KeySize, len(raw))
+	}
+	return raw, nil
+}
+
+func sortedIDs(keys map[string]string) []string {
+	ids := make([]string, 0, len(keys))
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ActiveKeyID mengembalikan key id yang dipakai untuk DEK baru.
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// NewDataKey membuat DEK baru, dibungkus KEK aktif. keyID dan wrapped
+// disimpan di dokumen untuk membukanya kembali lewat OpenDataKey.
+func (k *Keyring) NewDataKey() (dk *DataKey, keyID string, wrapped []byte, err error) {
+	raw := make([]byte, KeySize)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", nil, err
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	wrapped, err = seal(k.keks[k.active], raw, []byte(k.active))
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return &DataKey{aead: aead}, k.active, wrapped, nil
+}
+
+// OpenDataKey membuka DEK yang dibungkus KEK keyID.
+func (k *Keyring) OpenDataKey(keyID string, wrapped []byte) (*DataKey, error) {
+	kek, ok := k.keks[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+	raw, err := open(kek, wrapped, []byte(keyID))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &DataKey{aead: aead}, nil
+}
+
+// BlindIndex mengembalikan HMAC-SHA256 (hex) dari s yang sudah
+// dinormalisasi (trim, huruf kecil), untuk pencarian exact match tanpa
+// menyimpan plaintext. String kosong menghasilkan string kosong.
+func (k *Keyring) BlindIndex(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, k.index)
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// DataKey adalah DEK yang sudah dibuka.
+type DataKey struct {
+	aead cipher.AEAD
+}
+
+// Encrypt mengenkripsi plain. aad mengikat ciphertext ke konteksnya
+// (misalnya nama field dan id dokumen) agar tidak bisa dipindah ke dokumen
+// lain. String kosong tetap kosong.
+func (d *DataKey) Encrypt(plain, aad string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+	b, err := seal(d.aead, []byte(plain), []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	return prefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Decrypt membuka nilai dari Encrypt. Nilai tanpa prefix dikembalikan apa
+// adanya sebagai plaintext lama.
+func (d *DataKey) Decrypt(s, aad string) (string, error) {
+	if !IsEncrypted(s) {
+		return s, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, prefix))
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	plain, err := open(d.aead, b, []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// IsEncrypted melaporkan apakah s adalah hasil Encrypt.
+func IsEncrypted(s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal mengembalikan nonce diikuti ciphertext.
+func seal(aead cipher.AEAD, plain, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plain)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plain, aad), nil
+}
+
+func open(aead cipher.AEAD, b, aad []byte) ([]byte, error) {
+	n := aead.NonceSize()
+	if len(b) < n {
+		return nil, ErrDecrypt
+	}
+	plain, err := aead.Open(nil, b[:n], b[n:], aad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
@@ -0,0 +1,181 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, KeySize))
+}
+
+func mustKeyring(t *testing.T, keys map[string]string, active string) *Keyring {
+	t.Helper()
+	k, err := ParseKeyring(keys, active, testKey(0xbb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	k := mustKeyring(t, map[string]string{"k1": testKey(1)}, "k1")
+	dk, id, wrapped, err := k.NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "k1" {
+		t.Errorf("key id = %q, want k1", id)
+	}
+
+	ct, err := dk.Encrypt("0812-3456-7890", "no_telepon:abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(ct) || bytes.Contains([]byte(ct), []byte("0812")) {
+		t.Fatalf("ciphertext %q", ct)
+	}
+	again, _ := dk.Encrypt("0812-3456-7890", "no_telepon:abc")
+	if again == ct {
+		t.Error("same plaintext produced the same ciphertext")
+	}
+
+	opened, err := k.OpenDataKey(id, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := opened.Decrypt(ct, "no_telepon:abc"); err != nil || got != "0812-3456-7890" {
+		t.Errorf("Decrypt = %q, %v", got, err)
+	}
+}
+
+func TestEncryptEmptyAndLegacy(t *testing.T) {
+	k := mustKeyring(t, map[string]string{"k1": testKey(1)}, "k1")
+	dk, _, _, err := k.NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct, err := dk.Encrypt("", "alamat:abc"); err != nil || ct != "" {
+		t.Errorf("Encrypt(\"\") = %q, %v", ct, err)
+	}
+	if got, err := dk.Decrypt("Jl. Merdeka 1", "alamat:abc"); err != nil || got != "Jl. Merdeka 1" {
+		t.Errorf("legacy plaintext = %q, %v", got, err)
+	}
+}
+
+func TestDecryptRejectsWrongAADAndTampering(t *testing.T) {
+	k := mustKeyring(t, map[string]string{"k1": testKey(1)}, "k1")
+	dk, _, _, err := k.NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := dk.Encrypt("budi@example.com", "email:abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, aad := range []string{"email:abd", "alamat:abc", ""} {
+		if _, err := dk.Decrypt(ct, aad); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("Decrypt with aad %q: err = %v, want ErrDecrypt", aad, err)
+		}
+	}
+
+	b, _ := base64.StdEncoding.DecodeString(ct[len(prefix):])
+	b[len(b)-1] ^= 1
+	if _, err := dk.Decrypt(prefix+base64.StdEncoding.EncodeToString(b), "email:abc"); err == nil {
+		t.Error("tampered ciphertext decrypted")
+	}
+
+	other, _, _, _ := k.NewDataKey()
+	if _, err := other.Decrypt(ct, "email:abc"); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Decrypt with another data key: err = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestOldKeyStillOpensAfterRotation(t *testing.T) {
+	before := mustKeyring(t, map[string]string{"k1": testKey(1)}, "k1")
+	dk, id, wrapped, err := before.NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, _ := dk.Encrypt("Jl. Merdeka 1", "alamat:abc")
+
+	// k2 ditambahkan dan dijadikan aktif; k1 tetap ada sampai rotasi selesai
+	after := mustKeyring(t, map[string]string{"k1": testKey(1), "k2": testKey(2)}, "k2")
+	old, err := after.OpenDataKey(id, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := old.Decrypt(ct, "alamat:abc"); err != nil || got != "Jl. Merdeka 1" {
+		t.Errorf("Decrypt with old key = %q, %v", got, err)
+	}
+	if _, id, _, _ := after.NewDataKey(); id != "k2" {
+		t.Errorf("new data key wrapped by %q, want k2", id)
+	}
+
+	// data key k1 tidak bisa dibuka dengan k2 meski key id diganti
+	if _, err := after.OpenDataKey("k2", wrapped); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("OpenDataKey with wrong key id: err = %v, want ErrDecrypt", err)
+	}
+
+	removed := mustKeyring(t, map[string]string{"k2": testKey(2)}, "k2")
+	if _, err := removed.OpenDataKey(id, wrapped); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("OpenDataKey after removing k1: err = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	k := mustKeyring(t, map[string]string{"k1": testKey(1)}, "k1")
+	idx := k.BlindIndex("budi@example.com")
+	if len(idx) != 64 {
+		t.Fatalf("BlindIndex = %q, want 64 hex characters", idx)
+	}
+	for _, s := range []string{"budi@example.com", "Budi@Example.COM", "  budi@example.com\t"} {
+		if got := k.BlindIndex(s); got != idx {
+			t.Errorf("BlindIndex(%q) = %s, want %s", s, got, idx)
+		}
+	}
+	if k.BlindIndex("siti@example.com") == idx {
+		t.Error("different emails share a blind index")
+	}
+	if k.BlindIndex("  ") != "" {
+		t.Error("blank value should have an empty blind index")
+	}
+
+	// tidak bergantung pada KEK, hanya pada blind index key
+	rotated := mustKeyring(t, map[string]string{"k2": testKey(2)}, "k2")
+	if rotated.BlindIndex("budi@example.com") != idx {
+		t.Error("blind index changed with the active key")
+	}
+	otherIndex, err := ParseKeyring(map[string]string{"k1": testKey(1)}, "k1", testKey(0xcc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otherIndex.BlindIndex("budi@example.com") == idx {
+		t.Error("blind index does not depend on the blind index key")
+	}
+}
+
+func TestParseKeyringRejectsBadConfig(t *testing.T) {
+	short := base64.StdEncoding.EncodeToString([]byte("short"))
+	tests := []struct {
+		name   string
+		keys   map[string]string
+		active string
+		index  string
+	}{
+		{"no keys", nil, "k1", testKey(9)},
+		{"unknown active key", map[string]string{"k1": testKey(1)}, "k2", testKey(9)},
+		{"short key", map[string]string{"k1": short}, "k1", testKey(9)},
+		{"not base64", map[string]string{"k1": "not base64!"}, "k1", testKey(9)},
+		{"bad key id", map[string]string{"k:1": testKey(1)}, "k:1", testKey(9)},
+		{"short blind index key", map[string]string{"k1": testKey(1)}, "k1", short},
+	}
+	for _, tt := range tests {
+		if _, err := ParseKeyring(tt.keys, tt.active, tt.index); err == nil {
+			t.Errorf("%s: ParseKeyring accepted invalid config", tt.name)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Key adalah kunci pesan yang stabil; teksnya boleh berubah, kuncinya tidak.
+type Key string
+
+const (
+	LangID = "id"
+	LangEN = "en"
+)
+
+var (
+	mu          sync.RWMutex
+	defaultLang = LangID
+)
+
+// SetDefaultLanguage mengatur bahasa yang dipakai ketika Accept-Language
+// kosong atau tidak ada bahasa yang didukung.
+func SetDefaultLanguage(lang string) error {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if _, ok := catalog[lang]; !ok {
+		return fmt.Errorf("unsupported language %q", lang)
+	}
+	mu.Lock()
+	defaultLang = lang
+	mu.Unlock()
+	return nil
+}
+
+// DefaultLanguage mengembalikan bahasa default yang sedang aktif.
+func DefaultLanguage() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultLang
+}
+
+// Supported mengembalikan daftar bahasa yang ada di katalog.
+func Supported() []string {
+	langs := make([]string, 0, len(catalog))
+	for lang := range catalog {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Language memilih bahasa dari header Accept-Language request.
+func Language(r *http.Request) string {
+	if r == nil {
+		return DefaultLanguage()
+	}
+	return Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// Negotiate memilih bahasa terbaik dari nilai Accept-Language,
+// contoh: "en-US,en;q=0.9,id;q=0.8".
+func Negotiate(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		base := strings.SplitN(tag, "-", 2)[0]
+		if _, ok := catalog[base]; !ok || q <= bestQ {
+			continue
+		}
+		best, bestQ = base, q
+	}
+
+	if best == "" {
+		return DefaultLanguage()
+	}
+	return best
+}
+
+// T menerjemahkan key ke bahasa yang diminta oleh request.
+func T(r *http.Request, key Key, args ...interface{}) string {
+	return Translate(Language(r), key, args...)
+}
+
+// Translate menerjemahkan key ke bahasa tertentu. Jika key tidak ada di
+// bahasa tersebut, dipakai bahasa default, lalu key itu sendiri.
+func Translate(lang string, key Key, args ...interface{}) string {
+	msg, ok := catalog[lang][key]
+	if !ok {
+		msg, ok = catalog[DefaultLanguage()][key]
+	}
+	if !ok {
+		msg = string(key)
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
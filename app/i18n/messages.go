@@ -0,0 +1,168 @@
+package i18n
+
+// Kunci pesan. Jangan mengganti nilai string yang sudah ada karena klien
+// boleh bergantung padanya; tambahkan kunci baru jika perlu.
+const (
+	// Umum
+	MsgInvalidInput    Key = "common.invalid_input"
+	MsgInvalidID       Key = "common.invalid_id"
+	MsgInternalError   Key = "common.internal_error"
+	MsgUnauthorized    Key = "common.unauthorized"
+	MsgForbidden       Key = "common.forbidden"
+	MsgUserNotInCtx    Key = "common.user_not_in_context"
+	MsgDataNotFound    Key = "common.data_not_found"
+	MsgUpdated         Key = "common.updated"
+	MsgDeleted         Key = "common.deleted"
+	MsgRestored        Key = "common.restored"
+	MsgHardDeleted     Key = "common.hard_deleted"
+	MsgNothingRestore  Key = "common.nothing_to_restore"
+	MsgAlreadyRestore  Key = "common.already_restored"
+	MsgNotInTrash      Key = "common.not_in_trash"
+	MsgTrashEmpty      Key = "common.trash_empty"
+	MsgTooManyRequests Key = "common.too_many_requests"
+	MsgInvalidInclude  Key = "common.invalid_include"
+	MsgInvalidFilter   Key = "common.invalid_filter"
+	MsgInvalidSearch   Key = "common.invalid_search"
+	MsgQueryTimeout    Key = "common.query_timeout"
+	MsgInvalidQuery    Key = "common.invalid_query"
+	MsgInvalidCursor   Key = "common.invalid_cursor"
+	MsgInvalidParam    Key = "common.invalid_param"
+
+	// Auth
+	MsgRequiredCredentials Key = "auth.required_fields"
+	MsgPasswordTooShort    Key = "auth.password_too_short"
+	MsgUserExists          Key = "auth.user_exists"
+	MsgRegistered          Key = "auth.registered"
+	MsgInvalidCredentials  Key = "auth.invalid_credentials"
+	MsgTokenInvalid        Key = "auth.token_invalid"
+	MsgTokenUserNotFound   Key = "auth.user_not_found"
+	MsgCSRFInvalid         Key = "auth.csrf_invalid"
+	MsgLoggedOut           Key = "auth.logged_out"
+
+	// User
+	MsgUserNotFound         Key = "user.not_found"
+	MsgUserSoftDeleted      Key = "user.soft_deleted"
+	MsgUserSoftDeleteFailed Key = "user.soft_delete_failed"
+
+	// Alumni
+	MsgAlumniNotFound     Key = "alumni.not_found"
+	MsgAlumniCreateFailed Key = "alumni.create_failed"
+	MsgAlumniUpdateFailed Key = "alumni.update_failed"
+	MsgAlumniDeleteFailed Key = "alumni.delete_failed"
+
+	// Pekerjaan
+	MsgPekerjaanNotFound          Key = "pekerjaan.not_found"
+	MsgPekerjaanCreateFailed      Key = "pekerjaan.create_failed"
+	MsgPekerjaanUpdateFailed      Key = "pekerjaan.update_failed"
+	MsgPekerjaanAdminDeleteFailed Key = "pekerjaan.soft_delete_admin_failed"
+	MsgPekerjaanUserDeleteFailed  Key = "pekerjaan.soft_delete_user_failed"
+	MsgPekerjaanAllSoftDeleted    Key = "pekerjaan.all_soft_deleted"
+	MsgPekerjaanSoftDeleted       Key = "pekerjaan.soft_deleted"
+)
+
+var catalog = map[string]map[Key]string{
+	LangID: {
+		MsgInvalidInput:    "Input tidak valid",
+		MsgInvalidID:       "Format ID tidak valid",
+		MsgInternalError:   "Terjadi kesalahan pada server",
+		MsgUnauthorized:    "Tidak terautentikasi",
+		MsgForbidden:       "Akses ditolak",
+		MsgUserNotInCtx:    "Tidak terautentikasi: user tidak ditemukan di konteks",
+		MsgDataNotFound:    "Data tidak ditemukan",
+		MsgUpdated:         "Berhasil diperbarui",
+		MsgDeleted:         "Berhasil dihapus",
+		MsgRestored:        "Data berhasil dipulihkan",
+		MsgHardDeleted:     "Data berhasil dihapus permanen",
+		MsgNothingRestore:  "Tidak ada data yang bisa dipulihkan",
+		MsgAlreadyRestore:  "Data tidak ditemukan atau sudah dipulihkan",
+		MsgNotInTrash:      "Data tidak ditemukan atau tidak ada di sampah",
+		MsgTrashEmpty:      "Tidak ada data di sampah",
+		MsgTooManyRequests: "Terlalu banyak permintaan, coba lagi nanti",
+		MsgInvalidInclude:  "Nilai include tidak dikenal: %s",
+		MsgInvalidFilter:   "Nilai filter tidak valid: %s",
+		MsgInvalidSearch:   "Pencarian tidak valid: maksimal %d karakter, search_mode salah satu dari contains, prefix, exact, regex",
+		MsgQueryTimeout:    "Query terlalu lama, persempit pencarian atau filter",
+		MsgInvalidQuery:    "Parameter q wajib diisi, maksimal %d karakter",
+		MsgInvalidCursor:   "Cursor tidak valid atau tidak cocok dengan sortBy/order",
+		MsgInvalidParam:    "Parameter %s tidak valid",
+
+		MsgRequiredCredentials: "Email, username, dan password wajib diisi",
+		MsgPasswordTooShort:    "Password minimal 6 karakter",
+		MsgUserExists:          "User sudah terdaftar atau terjadi kesalahan database",
+		MsgRegistered:          "User berhasil didaftarkan",
+		MsgInvalidCredentials:  "Username atau password salah",
+		MsgTokenInvalid:        "Token tidak sesuai",
+		MsgTokenUserNotFound:   "User tidak ditemukan",
+		MsgCSRFInvalid:         "Token CSRF tidak ada atau tidak sesuai",
+		MsgLoggedOut:           "Berhasil logout",
+
+		MsgUserNotFound:         "User tidak ditemukan",
+		MsgUserSoftDeleted:      "User berhasil dihapus",
+		MsgUserSoftDeleteFailed: "Gagal menghapus user",
+
+		MsgAlumniNotFound:     "Alumni tidak ditemukan",
+		MsgAlumniCreateFailed: "Gagal menambahkan alumni",
+		MsgAlumniUpdateFailed: "Gagal memperbarui alumni",
+		MsgAlumniDeleteFailed: "Gagal menghapus alumni",
+
+		MsgPekerjaanNotFound:          "Pekerjaan tidak ditemukan",
+		MsgPekerjaanCreateFailed:      "Gagal menambahkan pekerjaan",
+		MsgPekerjaanUpdateFailed:      "Gagal memperbarui pekerjaan",
+		MsgPekerjaanAdminDeleteFailed: "Gagal menghapus riwayat pekerjaan alumni",
+		MsgPekerjaanUserDeleteFailed:  "Gagal menghapus pekerjaan",
+		MsgPekerjaanAllSoftDeleted:    "Semua riwayat pekerjaan alumni berhasil dihapus",
+		MsgPekerjaanSoftDeleted:       "Pekerjaan berhasil dihapus",
+	},
+	LangEN: {
+		MsgInvalidInput:    "Invalid input",
+		MsgInvalidID:       "Invalid ID format",
+		MsgInternalError:   "Internal server error",
+		MsgUnauthorized:    "Unauthorized",
+		MsgForbidden:       "Forbidden",
+		MsgUserNotInCtx:    "Unauthorized: user not found in context",
+		MsgDataNotFound:    "Data not found",
+		MsgUpdated:         "Updated successfully",
+		MsgDeleted:         "Deleted successfully",
+		MsgRestored:        "Data restored successfully",
+		MsgHardDeleted:     "Data permanently deleted",
+		MsgNothingRestore:  "No data found to restore",
+		MsgAlreadyRestore:  "Data not found or already restored",
+		MsgNotInTrash:      "Data not found or not in trash",
+		MsgTrashEmpty:      "No data found in trash",
+		MsgTooManyRequests: "Too many requests, please try again later",
+		MsgInvalidInclude:  "Unknown include value: %s",
+		MsgInvalidFilter:   "Invalid filter value: %s",
+		MsgInvalidSearch:   "Invalid search: at most %d characters, search_mode must be one of contains, prefix, exact, regex",
+		MsgQueryTimeout:    "Query took too long, narrow the search or filters",
+		MsgInvalidQuery:    "Parameter q is required, at most %d characters",
+		MsgInvalidCursor:   "Invalid cursor or cursor does not match sortBy/order",
+		MsgInvalidParam:    "Invalid parameter %s",
+
+		MsgRequiredCredentials: "Email, Username, and Password are required",
+		MsgPasswordTooShort:    "Password must be at least 6 characters",
+		MsgUserExists:          "User already exists or DB error",
+		MsgRegistered:          "User registered successfully",
+		MsgInvalidCredentials:  "Invalid credentials",
+		MsgTokenInvalid:        "Invalid token",
+		MsgTokenUserNotFound:   "User not found",
+		MsgCSRFInvalid:         "Missing or invalid CSRF token",
+		MsgLoggedOut:           "Logged out",
+
+		MsgUserNotFound:         "User not found",
+		MsgUserSoftDeleted:      "User soft deleted",
+		MsgUserSoftDeleteFailed: "Failed to soft delete user",
+
+		MsgAlumniNotFound:     "Alumni not found",
+		MsgAlumniCreateFailed: "Failed to create alumni",
+		MsgAlumniUpdateFailed: "Failed to update alumni",
+		MsgAlumniDeleteFailed: "Failed to delete alumni",
+
+		MsgPekerjaanNotFound:          "Pekerjaan not found",
+		MsgPekerjaanCreateFailed:      "Failed to create pekerjaan",
+		MsgPekerjaanUpdateFailed:      "Failed to update pekerjaan",
+		MsgPekerjaanAdminDeleteFailed: "Failed to soft delete pekerjaan (admin)",
+		MsgPekerjaanUserDeleteFailed:  "Failed to soft delete pekerjaan (user)",
+		MsgPekerjaanAllSoftDeleted:    "All of the alumni's job history was deleted",
+		MsgPekerjaanSoftDeleted:       "Pekerjaan deleted successfully",
+	},
+}
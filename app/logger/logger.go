@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+type ctxKey int
+
+const infoKey ctxKey = iota
+
+// requestInfo dibagikan lewat context selama satu request. Pointer dipakai
+// agar middleware di dalam (misalnya auth) bisa mengisi data yang dibaca
+// oleh access log di luar.
+type requestInfo struct {
+	mu        sync.RWMutex
+	requestID string
+	userID    string
+}
+
+// Setup memasang slog default sesuai level ("debug", "info", "warn",
+// "error") dan format ("json" atau "text").
+func Setup(w io.Writer, level, format string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	case "text":
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format %q (expected json or text)", format)
+	}
+
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+// WithRequestID menyimpan request ID di context.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, infoKey, &requestInfo{requestID: id})
+}
+
+// RequestID mengembalikan request ID dari context, atau "" jika tidak ada.
+func RequestID(ctx context.Context) string {
+	info, ok := ctx.Value(infoKey).(*requestInfo)
+	if !ok {
+		return ""
+	}
+	return info.requestID
+}
+
+// SetUserID mencatat user yang sedang login untuk request ini.
+func SetUserID(ctx context.Context, id string) {
+	if info, ok := ctx.Value(infoKey).(*requestInfo); ok {
+		info.mu.Lock()
+		info.userID = id
+		info.mu.Unlock()
+	}
+}
+
+// UserID mengembalikan user yang dicatat oleh SetUserID.
+func UserID(ctx context.Context) string {
+	info, ok := ctx.Value(infoKey).(*requestInfo)
+	if !ok {
+		return ""
+	}
+	info.mu.RLock()
+	defer info.mu.RUnlock()
+	return info.userID
+}
+
+// FromContext mengembalikan logger yang sudah membawa request_id (dan
+// user_id jika sudah diketahui).
+func FromContext(ctx context.Context) *slog.Logger {
+	l := slog.Default()
+	if id := RequestID(ctx); id != "" {
+		l = l.With("request_id", id)
+	}
+	if uid := UserID(ctx); uid != "" {
+		l = l.With("user_id", uid)
+	}
+	return l
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Semua metric didaftarkan ke registry default Prometheus, yang juga
+// berisi metric runtime Go dan proses.
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by mux route template, method and status.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by mux route template, method and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	mongoOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongo_operations_total",
+		Help: "MongoDB commands by collection, command and outcome.",
+	}, []string{"collection", "operation", "outcome"})
+
+	mongoDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongo_operation_duration_seconds",
+		Help:    "MongoDB command latency by collection and command.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"collection", "operation"})
+
+	// Logins menghitung percobaan login; label result: "success" atau "failure".
+	Logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_logins_total",
+		Help: "Login attempts by result.",
+	}, []string{"result"})
+
+	// SoftDeletes menghitung soft delete; scope "single" untuk satu data,
+	// "alumni" untuk semua data milik satu alumni.
+	SoftDeletes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "soft_deletes_total",
+		Help: "Soft deletes by resource and scope.",
+	}, []string{"resource", "scope"})
+
+	// Restores menghitung data yang dipulihkan dari trash.
+	Restores = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "restores_total",
+		Help: "Restores from trash by resource and scope.",
+	}, []string{"resource", "scope"})
+)
+
+// ObserveHTTP mencatat satu request HTTP.
+func ObserveHTTP(route, method, status string, d time.Duration) {
+	httpRequests.WithLabelValues(route, method, status).Inc()
+	httpDuration.WithLabelValues(route, method, status).Observe(d.Seconds())
+}
+
+// ObserveMongo mencatat satu perintah Mongo.
+func ObserveMongo(collection, operation string, d time.Duration, failed bool) {
+	outcome := "success"
+	if failed {
+		outcome = "error"
+	}
+	mongoOperations.WithLabelValues(collection, operation, outcome).Inc()
+	mongoDuration.WithLabelValues(collection, operation).Observe(d.Seconds())
+}
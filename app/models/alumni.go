@@ -16,11 +16,55 @@ type Alumni struct {
 	Email       string             `bson:"email" json:"email"`
 	No_telp     string             `bson:"no_telepon" json:"no_telepon"`
 	Alamat      string             `bson:"alamat" json:"alamat"`
+	Privacy     PrivacySettings    `bson:"privacy" json:"privacy"`
 	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	// Email, No_telp dan Alamat disimpan terenkripsi dengan data key di
+	// Encryption; EmailIndex adalah blind index untuk pencarian email.
+	// Keduanya hanya dipakai repository.
+	Encryption *FieldEncryption `bson:"encryption,omitempty" json:"-"`
+	EmailIndex string           `bson:"email_bidx,omitempty" json:"-"`
+}
+
+// FieldEncryption adalah data key dokumen yang dibungkus key KeyID.
+type FieldEncryption struct {
+	KeyID string `bson:"key_id"`
+	DEK   []byte `bson:"dek"`
+}
+
+// PrivacySettings adalah persetujuan alumni untuk menampilkan kontaknya ke
+// user lain. Default semua false: kontak hanya terlihat oleh admin dan
+// alumni itu sendiri.
+type PrivacySettings struct {
+	ShowEmail     bool `bson:"show_email" json:"show_email"`
+	ShowNoTelepon bool `bson:"show_no_telepon" json:"show_no_telepon"`
+	ShowAlamat    bool `bson:"show_alamat" json:"show_alamat"`
+}
+
+// Published mengembalikan field kontak (nama JSON) beserta izin tampilnya.
+func (p PrivacySettings) Published() map[string]bool {
+	return map[string]bool{
+		"email":      p.ShowEmail,
+		"no_telepon": p.ShowNoTelepon,
+		"alamat":     p.ShowAlamat,
+	}
 }
 
 type AlumniResponse struct {
 	Data []Alumni `json:"data"`
 	Meta MetaInfo `json:"meta"`
 }
+
+// AlumniWithPekerjaan adalah alumni beserta riwayat pekerjaan yang belum
+// dihapus (include=pekerjaan). CurrentJob berisi pekerjaan yang sedang
+// berjalan, atau null jika tidak ada.
+type AlumniWithPekerjaan struct {
+	Alumni     `bson:",inline"`
+	Pekerjaan  []Pekerjaan `bson:"pekerjaan" json:"pekerjaan"`
+	CurrentJob *Pekerjaan  `bson:"current_job,omitempty" json:"current_job"`
+}
+
+type AlumniWithPekerjaanResponse struct {
+	Data []AlumniWithPekerjaan `json:"data"`
+	Meta MetaInfo              `json:"meta"`
+}
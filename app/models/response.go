@@ -1,16 +1,26 @@
 package models
 
 type MetaInfo struct {
-	Page   int  `json:"page"`
-	Limit  int  `json:"limit"`
-	Total  int	`json:"total"`
-	Pages  int  `json:"pages"`
-	SortBy string `json:"sortBy"`
-	Order  string `json:"order"`
-	Search string `json:"search"`
+	Page  int `json:"page,omitempty"`
+	Limit int `json:"limit"`
+	// Total dan Pages kosong bila count=false
+	Total      *int   `json:"total,omitempty"`
+	Pages      *int   `json:"pages,omitempty"`
+	SortBy     string `json:"sortBy"`
+	Order      string `json:"order"`
+	Search     string `json:"search"`
+	SearchMode string `json:"searchMode"`
+	// Filters berisi filter terstruktur yang diterapkan, per nama parameter.
+	Filters map[string]interface{} `json:"filters,omitempty"`
+	// Next dan Prev adalah link halaman berikutnya/sebelumnya (juga di header Link).
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+	// NextCursor dan PrevCursor adalah token ?after= dan ?before= di mode cursor.
+	NextCursor *string `json:"nextCursor,omitempty"`
+	PrevCursor *string `json:"prevCursor,omitempty"`
 }
 
 type UserResponse struct {
-	Data []User  `json:"data"`
+	Data []User   `json:"data"`
 	Meta MetaInfo `json:"meta"`
-}
\ No newline at end of file
+}
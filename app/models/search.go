@@ -0,0 +1,20 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// SearchHit adalah satu hasil /search. Data berisi dokumen Alumni atau
+// Pekerjaan sesuai Type; Highlights berisi potongan field yang cocok dengan
+// kata yang cocok dibungkus <em>, sudah di-escape HTML.
+type SearchHit struct {
+	Type       string             `json:"type"`
+	ID         primitive.ObjectID `json:"id"`
+	Score      float64            `json:"score"`
+	Title      string             `json:"title"`
+	Highlights map[string]string  `json:"highlights"`
+	Data       interface{}        `json:"data"`
+}
+
+type SearchResponse struct {
+	Data []SearchHit `json:"data"`
+	Meta MetaInfo    `json:"meta"`
+}
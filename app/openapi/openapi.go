@@ -0,0 +1,145 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v3"
+)
+
+// Spesifikasi ditulis dalam YAML agar mudah dirawat, lalu disajikan sebagai
+// JSON. Setiap route baru di package routes wajib ditambahkan ke file ini;
+// Check akan melaporkan route yang belum terdokumentasi.
+//
+//go:embed openapi.yaml
+var specYAML []byte
+
+var methods = []string{"get", "post", "put", "patch", "delete"}
+
+// Spec adalah dokumen OpenAPI yang sudah diparse.
+type Spec struct {
+	json       []byte
+	operations map[string]bool // "GET /alumni/{}"
+}
+
+// Load memparse spesifikasi yang di-embed.
+func Load() (*Spec, error) {
+	var full map[string]interface{}
+	if err := yaml.Unmarshal(specYAML, &full); err != nil {
+		return nil, fmt.Errorf("parse openapi.yaml: %w", err)
+	}
+	if err := expandLegacy(full); err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(full)
+	if err != nil {
+		return nil, fmt.Errorf("encode openapi.json: %w", err)
+	}
+
+	s := &Spec{json: body, operations: map[string]bool{}}
+	for path, item := range full["paths"].(map[string]interface{}) {
+		item, _ := item.(map[string]interface{})
+		for _, m := range methods {
+			if _, ok := item[m]; ok {
+				s.operations[operationKey(m, path)] = true
+			}
+		}
+	}
+	return s, nil
+}
+
+// JSON mengembalikan spesifikasi dalam format JSON.
+func (s *Spec) JSON() []byte {
+	return s.json
+}
+
+// Check membandingkan route yang terdaftar di router dengan spesifikasi.
+// missing: route tanpa dokumentasi; stale: operasi di spesifikasi yang tidak
+// ada route-nya.
+func (s *Spec) Check(router *mux.Router) (missing, stale []string, err error) {
+	registered := map[string]bool{}
+	err = router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil // subrouter / prefix
+		}
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		ms, err := route.GetMethods()
+		if err != nil {
+			return fmt.Errorf("route %s has no method restriction", path)
+		}
+		for _, m := range ms {
+			key := operationKey(m, path)
+			registered[key] = true
+			if !s.operations[key] {
+				missing = append(missing, m+" "+path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for key := range s.operations {
+		if !registered[key] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+	return missing, stale, nil
+}
+
+// expandLegacy menyalin path pengganti untuk setiap alias di x-legacy-paths
+// dan menandai operasinya deprecated.
+func expandLegacy(doc map[string]interface{}) error {
+	legacy, _ := doc["x-legacy-paths"].(map[string]interface{})
+	delete(doc, "x-legacy-paths")
+	paths, ok := doc["paths"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("openapi.yaml: paths missing")
+	}
+
+	for alias, target := range legacy {
+		successor, _ := target.(string)
+		src, ok := paths[successor]
+		if !ok {
+			return fmt.Errorf("openapi.yaml: legacy path %s refers to unknown path %q", alias, successor)
+		}
+		var item map[string]interface{}
+		raw, _ := json.Marshal(src)
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return err
+		}
+		for _, m := range methods {
+			op, ok := item[m].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			desc, _ := op["description"].(string)
+			op["deprecated"] = true
+			op["description"] = fmt.Sprintf("Alias lama untuk `%s %s`; lihat header `Sunset`.\n\n%s",
+				strings.ToUpper(m), successor, desc)
+			if id, ok := op["operationId"].(string); ok {
+				op["operationId"] = id + "Legacy"
+			}
+		}
+		paths[alias] = item
+	}
+	return nil
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// operationKey menyamakan nama parameter path ({id}, {alumni_id:[0-9a-f]+})
+// karena OpenAPI menganggap keduanya path yang sama.
+func operationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + pathParam.ReplaceAllString(path, "{}")
+}
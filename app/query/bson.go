@@ -0,0 +1,141 @@
+package query
+
+import (
+	"regexp"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Compare membangun kondisi standar path <op> v.
+func Compare(path string, op Op, v interface{}) bson.M {
+	if op == Eq {
+		return bson.M{path: v}
+	}
+	return bson.M{path: bson.M{"$" + string(op): v}}
+}
+
+// And menggabungkan kondisi menjadi satu filter.
+func And(conds ...bson.M) bson.M {
+	switch len(conds) {
+	case 0:
+		return bson.M{}
+	case 1:
+		return conds[0]
+	}
+	return bson.M{"$and": conds}
+}
+
+// Match mengembalikan filter find/$match: base (misalnya is_deleted),
+// semua filter kecuali yang butuh stage agregasi, dan search.
+func (q Query) Match(base ...bson.M) bson.M {
+	var conds []bson.M
+	for _, b := range base {
+		if len(b) > 0 {
+			conds = append(conds, b)
+		}
+	}
+	for _, c := range q.Conditions {
+		switch {
+		case c.Field.Stages != nil:
+			continue
+		case c.Field.Cond != nil:
+			conds = append(conds, c.Field.Cond(c.Op, c.Value))
+		default:
+			conds = append(conds, Compare(c.Field.path(), c.Op, c.Value))
+		}
+	}
+	if or := q.searchConds(); len(or) > 0 {
+		conds = append(conds, bson.M{"$or": or})
+	}
+	return And(conds...)
+}
+
+func (q Query) searchConds() []bson.M {
+	if q.Search == "" {
+		return nil
+	}
+	pattern := searchPattern(q.Search, q.SearchMode)
+	var or []bson.M
+	for _, name := range q.schema.Search {
+		f := q.schema.field(name)
+		switch f.Type {
+		case String:
+			or = append(or, bson.M{f.path(): bson.M{"$regex": pattern, "$options": "i"}})
+		case Int:
+			// field angka hanya dicocokkan bila search berupa angka
+			if n, err := strconv.Atoi(q.Search); err == nil {
+				or = append(or, bson.M{f.path(): n})
+			}
+		}
+	}
+	return or
+}
+
+// searchPattern mengubah search menjadi pola $regex sesuai mode. Selain
+// mode Regex, semua karakter khusus di-escape.
+func searchPattern(search string, mode SearchMode) string {
+	quoted := regexp.QuoteMeta(search)
+	switch mode {
+	case Prefix:
+		return "^" + quoted
+	case Exact:
+		return "^" + quoted + "$"
+	case Regex:
+		return search
+	}
+	return quoted
+}
+
+// Stages mengembalikan stage agregasi untuk filter yang membutuhkannya.
+// Kosong berarti Match saja sudah cukup.
+func (q Query) Stages() []bson.D {
+	var stages []bson.D
+	for _, c := range q.Conditions {
+		if c.Field.Stages != nil {
+			stages = append(stages, c.Field.Stages(c.Op, c.Value)...)
+		}
+	}
+	return stages
+}
+
+// Pipeline mengembalikan $match diikuti Stages.
+func (q Query) Pipeline(base ...bson.M) mongo.Pipeline {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: q.Match(base...)}}}
+	return append(pipeline, q.Stages()...)
+}
+
+// Sort mengembalikan urutan pengambilan untuk find/$sort, dengan _id
+// sebagai penentu agar urutan stabil. Untuk ?before= urutannya dibalik;
+// Paginate membaliknya lagi.
+func (q Query) Sort() bson.D {
+	order := 1
+	if q.Order == "desc" {
+		order = -1
+	}
+	if q.Cursor != nil && q.Cursor.Before {
+		order = -order
+	}
+	path := q.sortPath()
+	if path == "_id" {
+		return bson.D{{Key: path, Value: order}}
+	}
+	return bson.D{{Key: path, Value: order}, {Key: "_id", Value: order}}
+}
+
+func (q Query) sortPath() string {
+	if f := q.schema.field(q.SortBy); f != nil {
+		return f.path()
+	}
+	return q.SortBy
+}
+
+// Skip adalah jumlah dokumen yang dilewati untuk halaman q.Page. Mode
+// cursor tidak memakai skip.
+func (q Query) Skip() int64 {
+	if q.Cursor != nil {
+		return 0
+	}
+	return int64((q.Page - 1) * q.Limit)
+}
@@ -0,0 +1,176 @@
+package query
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Nama parameter paging cursor.
+const (
+	AfterParam  = "after"
+	BeforeParam = "before"
+	CountParam  = "count"
+)
+
+// Cursor adalah posisi paging keyset: nilai sort key dan _id dokumen batas.
+// Token kosong (?after= atau ?before=) berarti mulai dari halaman pertama
+// atau terakhir.
+type Cursor struct {
+	Before bool
+	Value  bson.RawValue
+	ID     primitive.ObjectID
+}
+
+// Start melaporkan apakah cursor tidak punya posisi (halaman pertama untuk
+// after, halaman terakhir untuk before).
+func (c *Cursor) Start() bool {
+	return c.ID.IsZero()
+}
+
+// cursorToken adalah isi token. sortBy dan order ikut disimpan agar token
+// tidak dipakai dengan urutan lain.
+type cursorToken struct {
+	SortBy string             `bson:"s"`
+	Order  string             `bson:"o"`
+	Value  bson.RawValue      `bson:"v"`
+	ID     primitive.ObjectID `bson:"i"`
+}
+
+func (q Query) encodeCursor(v bson.RawValue, id primitive.ObjectID) string {
+	b, err := bson.Marshal(cursorToken{SortBy: q.SortBy, Order: q.Order, Value: v, ID: id})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (q Query) decodeCursor(token string) (Cursor, bool) {
+	if token == "" {
+		return Cursor{}, true
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, false
+	}
+	var t cursorToken
+	if err := bson.Unmarshal(b, &t); err != nil || t.ID.IsZero() {
+		return Cursor{}, false
+	}
+	if t.SortBy != q.SortBy || t.Order != q.Order || !cursorValueTypes[t.Value.Type] {
+		return Cursor{}, false
+	}
+	return Cursor{Value: t.Value, ID: t.ID}, true
+}
+
+// cursorValueTypes adalah tipe nilai sort key yang boleh ada di token.
+// Token dibuat klien, jadi dokumen, array, regex dan sejenisnya ditolak
+// karena akan dibaca Mongo sebagai operator query di Seek.
+var cursorValueTypes = map[bsontype.Type]bool{
+	bsontype.String:     true,
+	bsontype.Int32:      true,
+	bsontype.Int64:      true,
+	bsontype.Double:     true,
+	bsontype.Decimal128: true,
+	bsontype.Boolean:    true,
+	bsontype.DateTime:   true,
+	bsontype.Timestamp:  true,
+	bsontype.ObjectID:   true,
+	bsontype.Null:       true,
+}
+
+// CursorFor mengembalikan token posisi dokumen raw menurut urutan q.
+func (q Query) CursorFor(raw bson.Raw) string {
+	v, err := raw.LookupErr(strings.Split(q.sortPath(), ".")...)
+	if err != nil {
+		v = bson.RawValue{Type: bsontype.Null}
+	}
+	id, _ := raw.Lookup("_id").ObjectIDOK()
+	return q.encodeCursor(v, id)
+}
+
+// Seek mengembalikan kondisi keyset "sesudah/sebelum cursor", atau nil di
+// mode offset dan di awal cursor. Null (dan field yang tidak ada) diurutkan
+// Mongo sebelum nilai lain, jadi ikut ditangani.
+func (q Query) Seek() bson.M {
+	c := q.Cursor
+	if c == nil || c.Start() {
+		return nil
+	}
+	// arah nilai yang dicari: naik bila (asc, after) atau (desc, before)
+	up := (q.Order == "desc") == c.Before
+	cmp := "$lt"
+	if up {
+		cmp = "$gt"
+	}
+
+	path := q.sortPath()
+	if path == "_id" {
+		return bson.M{"_id": bson.M{cmp: c.ID}}
+	}
+	tie := bson.M{path: c.Value, "_id": bson.M{cmp: c.ID}}
+	if c.Value.Type == bsontype.Null {
+		if up {
+			return bson.M{"$or": []bson.M{tie, {path: bson.M{"$ne": nil}}}}
+		}
+		return tie
+	}
+	or := []bson.M{{path: bson.M{cmp: c.Value}}, tie}
+	if !up {
+		or = append(or, bson.M{path: nil})
+	}
+	return bson.M{"$or": or}
+}
+
+// Page adalah hasil paging satu listing.
+type Page struct {
+	// Total -1 berarti tidak dihitung (count=false).
+	Total   int
+	HasNext bool
+	HasPrev bool
+	// Next dan Prev adalah token cursor untuk ?after= dan ?before= di mode
+	// cursor. Token kosong berarti halaman pertama/terakhir.
+	Next string
+	Prev string
+}
+
+// Paginate memotong raws (hasil query dengan Limit+1) menjadi satu halaman
+// dalam urutan tampil dan mengisi Page. total adalah hasil count atau -1.
+func (q Query) Paginate(raws []bson.Raw, total int) ([]bson.Raw, Page) {
+	more := len(raws) > q.Limit
+	if more {
+		raws = raws[:q.Limit]
+	}
+	p := Page{Total: total}
+	if q.Cursor == nil {
+		p.HasNext = more
+		p.HasPrev = q.Page > 1
+		return raws, p
+	}
+
+	if q.Cursor.Before {
+		// diambil dengan urutan terbalik
+		for i, j := 0, len(raws)-1; i < j; i, j = i+1, j-1 {
+			raws[i], raws[j] = raws[j], raws[i]
+		}
+		p.HasPrev, p.HasNext = more, !q.Cursor.Start()
+	} else {
+		p.HasNext, p.HasPrev = more, !q.Cursor.Start()
+	}
+	if len(raws) == 0 {
+		// melewati ujung: arahkan kembali ke halaman pertama/terakhir
+		start := q.Cursor.Start()
+		p.HasNext, p.HasPrev = q.Cursor.Before && !start, !q.Cursor.Before && !start
+		return raws, p
+	}
+	if p.HasNext {
+		p.Next = q.CursorFor(raws[len(raws)-1])
+	}
+	if p.HasPrev {
+		p.Prev = q.CursorFor(raws[0])
+	}
+	return raws, p
+}
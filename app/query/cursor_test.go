@@ -0,0 +1,85 @@
+package query
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// craftToken membuat token seperti yang bisa dikirim klien sendiri.
+func craftToken(t *testing.T, sortBy, order string, v interface{}) string {
+	t.Helper()
+	b, err := bson.Marshal(bson.D{
+		{Key: "s", Value: sortBy},
+		{Key: "o", Value: order},
+		{Key: "v", Value: v},
+		{Key: "i", Value: primitive.NewObjectID()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	q := mustParse(t, "sortBy=nama&after=")
+	raw, err := bson.Marshal(bson.M{"_id": primitive.NewObjectID(), "nama": "Budi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := q.CursorFor(raw)
+	next := mustParse(t, "sortBy=nama&after="+token)
+	if next.Cursor == nil || next.Cursor.Start() || next.Cursor.Value.StringValue() != "Budi" {
+		t.Fatalf("cursor = %+v", next.Cursor)
+	}
+	if next.Seek() == nil {
+		t.Error("Seek() = nil for a positioned cursor")
+	}
+}
+
+func TestCursorRejectsCraftedTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{"operator document", bson.D{{Key: "$ne", Value: nil}}},
+		{"regex operator", bson.D{{Key: "$regex", Value: ".*"}}},
+		{"array", bson.A{"a", "b"}},
+		{"regex", primitive.Regex{Pattern: ".*"}},
+		{"javascript", primitive.JavaScript("function() { return true }")},
+		{"code with scope", primitive.CodeWithScope{Code: "x", Scope: bson.D{}}},
+		{"dbpointer", primitive.DBPointer{DB: "alumni", Pointer: primitive.NewObjectID()}},
+		{"symbol", primitive.Symbol("x")},
+		{"min key", primitive.MinKey{}},
+		{"binary", primitive.Binary{Data: []byte("x")}},
+	}
+	for _, tt := range tests {
+		for _, param := range []string{AfterParam, BeforeParam} {
+			t.Run(tt.name+"/"+param, func(t *testing.T) {
+				v := url.Values{"sortBy": {"nama"}, param: {craftToken(t, "nama", "asc", tt.value)}}
+				_, err := Parse(v, testSchema)
+				var qerr *Error
+				if !errors.As(err, &qerr) || qerr.Param != param {
+					t.Errorf("Parse error = %v, want invalid %s", err, param)
+				}
+			})
+		}
+	}
+}
+
+func TestCursorRejectsOtherSort(t *testing.T) {
+	token := craftToken(t, "nama", "asc", "Budi")
+	for _, raw := range []string{"sortBy=angkatan&after=", "sortBy=nama&order=desc&after="} {
+		v, _ := url.ParseQuery(raw + token)
+		if _, err := Parse(v, testSchema); err == nil {
+			t.Errorf("Parse(%q) accepted a token for another sort", raw)
+		}
+	}
+	if _, err := Parse(url.Values{"sortBy": {"nama"}, AfterParam: {"not base64!"}}, testSchema); err == nil {
+		t.Error("Parse accepted a malformed token")
+	}
+}
@@ -0,0 +1,79 @@
+package query
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// FieldsParam adalah parameter sparse fieldset, contoh ?fields=nama,jurusan.
+const FieldsParam = "fields"
+
+// Fieldset adalah field response (nama JSON) yang boleh dilihat per role.
+type Fieldset struct {
+	Roles map[string][]string
+	// Default dipakai role yang tidak ada di Roles.
+	Default []string
+}
+
+// Allowed mengembalikan field yang boleh dilihat role.
+func (f Fieldset) Allowed(role string) []string {
+	if fields, ok := f.Roles[role]; ok {
+		return fields
+	}
+	return f.Default
+}
+
+// Parse membaca ?fields= untuk role. Kosong berarti semua field yang boleh
+// dilihat role; field di luar allowlist menghasilkan *Error. id selalu
+// disertakan.
+func (f Fieldset) Parse(raw, role string) ([]string, error) {
+	allowed := f.Allowed(role)
+	if strings.TrimSpace(raw) == "" {
+		return allowed, nil
+	}
+	ok := make(map[string]bool, len(allowed))
+	for _, a := range allowed {
+		ok[a] = true
+	}
+
+	fields := []string{"id"}
+	seen := map[string]bool{"id": true}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if !ok[name] {
+			return nil, &Error{Param: FieldsParam}
+		}
+		seen[name] = true
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// Projection membangun projection Mongo dari nama field JSON, ditambah
+// path extra (misalnya field sort yang dibutuhkan cursor). nil berarti
+// tanpa projection.
+func Projection(fields []string, extra ...string) bson.M {
+	if fields == nil {
+		return nil
+	}
+	p := bson.M{"_id": 1}
+	for _, f := range fields {
+		if f != "id" {
+			p[f] = 1
+		}
+	}
+	for _, f := range extra {
+		p[f] = 1
+	}
+	return p
+}
+
+// Projection mengembalikan projection untuk q.Fields. Field sort selalu
+// ikut karena dibutuhkan token cursor.
+func (q Query) Projection() bson.M {
+	return Projection(q.Fields, q.sortPath())
+}
@@ -0,0 +1,313 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultPage = 1
+
+	// DefaultLimit dan MaxLimit dipakai skema yang tidak mengaturnya sendiri.
+	DefaultLimit = 10
+	MaxLimit     = 100
+
+	// MaxSearchLength adalah panjang maksimal ?search= dalam karakter.
+	MaxSearchLength = 100
+)
+
+// SearchMode menentukan cara ?search= dicocokkan ke field teks.
+type SearchMode string
+
+const (
+	// Contains (default) mencari teks di mana saja; karakter regex di-escape.
+	Contains SearchMode = "contains"
+	// Prefix mencocokkan awal teks.
+	Prefix SearchMode = "prefix"
+	// Exact mencocokkan seluruh teks (tanpa membedakan huruf besar/kecil).
+	Exact SearchMode = "exact"
+	// Regex memakai search apa adanya sebagai pola regex. Harus diminta
+	// eksplisit dan tetap dibatasi panjang serta maxTimeMS.
+	Regex SearchMode = "regex"
+)
+
+// Nama parameter pencarian; juga dipakai sebagai Error.Param.
+const (
+	SearchParam     = "search"
+	SearchModeParam = "search_mode"
+)
+
+// Error menandai parameter query yang tidak valid.
+type Error struct {
+	Param string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("query: invalid parameter %s", e.Param)
+}
+
+// Condition adalah satu filter yang sudah divalidasi.
+type Condition struct {
+	Field *Field
+	Op    Op
+	Value interface{}
+}
+
+// Query adalah parameter listing yang sudah divalidasi terhadap skema.
+type Query struct {
+	Page       int
+	Limit      int
+	SortBy     string
+	Order      string
+	Search     string
+	SearchMode SearchMode
+	Conditions []Condition
+	// Cursor tidak nil di mode cursor (?after= atau ?before=); Page lalu
+	// diabaikan.
+	Cursor *Cursor
+	// Count false melewati penghitungan total. Default true di mode offset
+	// dan false di mode cursor.
+	Count bool
+	// Fields adalah field response yang diminta (lihat Fieldset); nil
+	// berarti dokumen utuh. Diisi pemanggil karena bergantung pada role.
+	Fields []string
+
+	schema  *Schema
+	filters map[string]interface{}
+}
+
+var filterParam = regexp.MustCompile(`^filter\[([A-Za-z0-9_]+)\](?:\[([a-z]+)\])?$`)
+
+// Parse membaca page, limit, sortBy, order, after/before, count, search dan
+// filter dari v.
+// Parameter lain diabaikan. sortBy di luar skema, serta filter dengan field
+// atau operator yang tidak dikenal skema, menghasilkan *Error.
+func Parse(v url.Values, s *Schema) (Query, error) {
+	defLimit, maxLimit := s.limits()
+	q := Query{
+		Page:    defaultPage,
+		Limit:   defLimit,
+		SortBy:  s.DefaultSort,
+		Order:   s.DefaultOrder,
+		Search:  v.Get(SearchParam),
+		schema:  s,
+		filters: map[string]interface{}{},
+	}
+	if p := v.Get("page"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 {
+			return Query{}, &Error{Param: "page"}
+		}
+		q.Page = n
+	}
+	if l := v.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 {
+			return Query{}, &Error{Param: "limit"}
+		}
+		// limit terlalu besar diturunkan, bukan ditolak; MetaInfo
+		// melaporkan limit yang dipakai
+		q.Limit = min(n, maxLimit)
+	}
+	if sortBy := v.Get("sortBy"); sortBy != "" {
+		if !s.sortable(sortBy) {
+			return Query{}, &Error{Param: "sortBy"}
+		}
+		q.SortBy = sortBy
+	}
+	switch order := v.Get("order"); order {
+	case "":
+	case "asc", "desc":
+		q.Order = order
+	default:
+		return Query{}, &Error{Param: "order"}
+	}
+	mode, err := parseSearch(q.Search, v.Get(SearchModeParam))
+	if err != nil {
+		return Query{}, err
+	}
+	q.SearchMode = mode
+	if err := q.parsePaging(v); err != nil {
+		return Query{}, err
+	}
+
+	// urutkan agar error dan hasil selalu sama untuk query yang sama
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var name string
+		var op Op
+		if m := filterParam.FindStringSubmatch(key); m != nil {
+			name, op = m[1], Op(m[2])
+			if op == "" {
+				op = Eq
+			}
+		} else if a, ok := s.Aliases[key]; ok {
+			name, op = a.Field, a.Op
+		} else {
+			continue
+		}
+
+		if strings.Join(v[key], "") == "" {
+			continue // ?jurusan= sama dengan tanpa filter
+		}
+		f := s.field(name)
+		if f == nil || !f.allows(op) {
+			return Query{}, &Error{Param: key}
+		}
+		val, err := parseValue(f.Type, op, v[key])
+		if err != nil {
+			return Query{}, &Error{Param: key}
+		}
+		q.Conditions = append(q.Conditions, Condition{Field: f, Op: op, Value: val})
+		q.filters[key] = echo(val)
+	}
+	return q, nil
+}
+
+// parsePaging membaca mode cursor dan count. sortBy dan order harus sudah
+// terisi karena token cursor terikat pada keduanya.
+func (q *Query) parsePaging(v url.Values) error {
+	switch {
+	case v.Has(AfterParam) && v.Has(BeforeParam):
+		return &Error{Param: BeforeParam}
+	case v.Has(AfterParam), v.Has(BeforeParam):
+		param := AfterParam
+		if v.Has(BeforeParam) {
+			param = BeforeParam
+		}
+		if v.Get("page") != "" {
+			return &Error{Param: "page"}
+		}
+		c, ok := q.decodeCursor(v.Get(param))
+		if !ok {
+			return &Error{Param: param}
+		}
+		c.Before = param == BeforeParam
+		q.Cursor = &c
+		q.Page = 0
+	}
+
+	q.Count = q.Cursor == nil
+	if s := v.Get(CountParam); s != "" {
+		count, err := strconv.ParseBool(s)
+		if err != nil {
+			return &Error{Param: CountParam}
+		}
+		q.Count = count
+	}
+	return nil
+}
+
+// Filters mengembalikan filter yang diterapkan per nama parameter, atau nil.
+func (q Query) Filters() map[string]interface{} {
+	if len(q.filters) == 0 {
+		return nil
+	}
+	return q.filters
+}
+
+// parseSearch memvalidasi search dan mode-nya. Mode kosong berarti Contains.
+func parseSearch(search, mode string) (SearchMode, error) {
+	m := SearchMode(mode)
+	switch m {
+	case "":
+		m = Contains
+	case Contains, Prefix, Exact, Regex:
+	default:
+		return "", &Error{Param: SearchModeParam}
+	}
+	if utf8.RuneCountInString(search) > MaxSearchLength {
+		return "", &Error{Param: SearchParam}
+	}
+	if m == Regex {
+		// sintaks RE2 tidak sama persis dengan PCRE milik Mongo, tapi cukup
+		// untuk menolak pola yang rusak sebelum sampai ke database
+		if _, err := regexp.Compile(search); err != nil {
+			return "", &Error{Param: SearchParam}
+		}
+	}
+	return m, nil
+}
+
+func parseValue(t Type, op Op, raw []string) (interface{}, error) {
+	if op != In && op != Nin {
+		if len(raw) != 1 {
+			return nil, fmt.Errorf("expected a single value")
+		}
+		return parseScalar(t, op, raw[0])
+	}
+
+	var out []interface{}
+	for _, r := range raw {
+		for _, s := range strings.Split(r, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			v, err := parseScalar(t, op, s)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("empty list")
+	}
+	return out, nil
+}
+
+func parseScalar(t Type, op Op, s string) (interface{}, error) {
+	switch t {
+	case Int:
+		return strconv.Atoi(s)
+	case Bool:
+		return strconv.ParseBool(s)
+	case ObjectID:
+		return primitive.ObjectIDFromHex(s)
+	case Time:
+		if ts, err := time.Parse(time.RFC3339, s); err == nil {
+			return ts, nil
+		}
+		ts, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, err
+		}
+		// tanggal saja: "<= hari itu" dan "> hari itu" berarti akhir hari
+		if op == Lte || op == Gt {
+			ts = ts.Add(24*time.Hour - time.Millisecond)
+		}
+		return ts, nil
+	}
+	if s == "" {
+		return nil, fmt.Errorf("empty value")
+	}
+	return s, nil
+}
+
+// echo mengubah nilai filter ke bentuk yang enak dibaca di MetaInfo.
+func echo(v interface{}) interface{} {
+	switch v := v.(type) {
+	case primitive.ObjectID:
+		return v.Hex()
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			out[i] = echo(v[i])
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,144 @@
+package query
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var testSchema = MustSchema(&Schema{
+	Fields: []Field{
+		{Name: "_id", Type: ObjectID},
+		{Name: "nama", Type: String, Ops: []Op{Eq}},
+		{Name: "jurusan", Type: String, Ops: Equality},
+		{Name: "angkatan", Type: Int, Ops: Comparison},
+		{Name: "aktif", Type: Bool, Ops: []Op{Eq}},
+	},
+	Aliases: map[string]Alias{
+		"jurusan":      {Field: "jurusan", Op: In},
+		"angkatan_min": {Field: "angkatan", Op: Gte},
+	},
+	Sort:         []string{"_id", "nama", "angkatan"},
+	DefaultSort:  "_id",
+	DefaultOrder: "asc",
+	Search:       []string{"nama"},
+})
+
+func mustParse(t *testing.T, raw string) Query {
+	t.Helper()
+	v, err := url.ParseQuery(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := Parse(v, testSchema)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", raw, err)
+	}
+	return q
+}
+
+func TestParseFilters(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want bson.M
+	}{
+		{"", bson.M{}},
+		{"filter[nama]=Budi", bson.M{"nama": "Budi"}},
+		{"filter[jurusan][eq]=TI", bson.M{"jurusan": "TI"}},
+		{"filter[jurusan][ne]=TI", bson.M{"jurusan": bson.M{"$ne": "TI"}}},
+		{"filter[jurusan][in]=TI,SI", bson.M{"jurusan": bson.M{"$in": []interface{}{"TI", "SI"}}}},
+		{"filter[jurusan][nin]=TI&filter[jurusan][nin]=SI", bson.M{"jurusan": bson.M{"$nin": []interface{}{"TI", "SI"}}}},
+		{"filter[angkatan][gte]=2018", bson.M{"angkatan": bson.M{"$gte": 2018}}},
+		{"filter[aktif]=true", bson.M{"aktif": true}},
+		{"jurusan=TI,SI", bson.M{"jurusan": bson.M{"$in": []interface{}{"TI", "SI"}}}},
+		{"angkatan_min=2018", bson.M{"angkatan": bson.M{"$gte": 2018}}},
+		{"jurusan=", bson.M{}},
+		{"unrelated=1", bson.M{}},
+		{"filter[angkatan][gte]=2018&filter[angkatan][lt]=2020", bson.M{"$and": []bson.M{
+			{"angkatan": bson.M{"$gte": 2018}},
+			{"angkatan": bson.M{"$lt": 2020}},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := mustParse(t, tt.raw).Match(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejectsInvalidParams(t *testing.T) {
+	tests := []struct {
+		raw, param string
+	}{
+		// field dan operator di luar skema
+		{"filter[email]=a@b.c", "filter[email]"},
+		{"filter[nama][ne]=Budi", "filter[nama][ne]"},
+		{"filter[nama][gt]=Budi", "filter[nama][gt]"},
+		{"filter[angkatan][like]=2018", "filter[angkatan][like]"},
+		{"filter[_id]=000000000000000000000000", "filter[_id]"},
+		// nilai yang tidak sesuai tipe
+		{"filter[angkatan]=baru", "filter[angkatan]"},
+		{"filter[aktif]=ya", "filter[aktif]"},
+		{"angkatan_min=x", "angkatan_min"},
+		// sort, order dan paging
+		{"sortBy=email", "sortBy"},
+		{"sortBy=jurusan", "sortBy"},
+		{"order=up", "order"},
+		{"page=0", "page"},
+		{"limit=abc", "limit"},
+		// search
+		{"search=a&search_mode=fuzzy", SearchModeParam},
+		{"search=(&search_mode=regex", SearchParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			v, _ := url.ParseQuery(tt.raw)
+			_, err := Parse(v, testSchema)
+			var qerr *Error
+			if !errors.As(err, &qerr) {
+				t.Fatalf("Parse(%q) error = %v, want *Error", tt.raw, err)
+			}
+			if qerr.Param != tt.param {
+				t.Errorf("Param = %q, want %q", qerr.Param, tt.param)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	q := mustParse(t, "")
+	if q.SortBy != "_id" || q.Order != "asc" {
+		t.Errorf("default sort = %s %s, want _id asc", q.SortBy, q.Order)
+	}
+	q = mustParse(t, "sortBy=angkatan&order=desc")
+	if q.SortBy != "angkatan" || q.Order != "desc" {
+		t.Errorf("sort = %s %s, want angkatan desc", q.SortBy, q.Order)
+	}
+}
+
+func TestParseSearchEscaping(t *testing.T) {
+	tests := []struct {
+		raw, pattern string
+	}{
+		{"search=a.b*", `a\.b\*`},
+		{"search=a.b*&search_mode=contains", `a\.b\*`},
+		{"search=(Budi)&search_mode=prefix", `^\(Budi\)`},
+		{"search=S1+TI&search_mode=exact", `^S1 TI$`},
+		{"search=^Bu.i$&search_mode=exact", `^\^Bu\.i\$$`},
+		{"search=" + url.QueryEscape(`{"$ne":null}`), `\{"\$ne":null\}`},
+		{"search=^Bu.i$&search_mode=regex", `^Bu.i$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			want := bson.M{"$or": []bson.M{{"nama": bson.M{"$regex": tt.pattern, "$options": "i"}}}}
+			if got := mustParse(t, tt.raw).Match(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Match() = %v, want %v", got, want)
+			}
+		})
+	}
+}
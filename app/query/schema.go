@@ -0,0 +1,174 @@
+// Package query membaca parameter listing (paging, sort, search dan filter)
+// dari query string, memvalidasinya terhadap skema field per resource, dan
+// menerjemahkannya ke BSON.
+//
+// Sintaks filter:
+//
+//	filter[angkatan][gte]=2018   operator eksplisit
+//	filter[jurusan]=TI           sama dengan filter[jurusan][eq]=TI
+//	filter[jurusan][in]=TI,SI    in/nin: dipisah koma atau parameter diulang
+//
+// Skema juga bisa mendaftarkan alias berupa parameter biasa, misalnya
+// angkatan_min sebagai filter[angkatan][gte].
+package query
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Type adalah tipe nilai field; menentukan cara parsing nilai filter.
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Bool
+	// Time menerima RFC3339 atau tanggal saja (YYYY-MM-DD, UTC).
+	Time
+	ObjectID
+)
+
+// Op adalah operator filter.
+type Op string
+
+const (
+	Eq  Op = "eq"
+	Ne  Op = "ne"
+	Gt  Op = "gt"
+	Gte Op = "gte"
+	Lt  Op = "lt"
+	Lte Op = "lte"
+	In  Op = "in"
+	Nin Op = "nin"
+)
+
+// Operator yang umum dipakai per jenis field.
+var (
+	Equality   = []Op{Eq, Ne, In, Nin}
+	Comparison = []Op{Eq, Ne, Gt, Gte, Lt, Lte, In, Nin}
+	Range      = []Op{Gt, Gte, Lt, Lte}
+)
+
+// Field adalah field yang dikenal sebuah resource.
+type Field struct {
+	// Name adalah nama field di API.
+	Name string
+	// Path adalah nama field di dokumen; kosong berarti sama dengan Name.
+	Path string
+	Type Type
+	// Ops adalah operator filter yang diizinkan; kosong berarti field ini
+	// tidak bisa difilter (hanya untuk sort atau search).
+	Ops []Op
+	// Cond membangun kondisi sendiri, untuk field virtual atau field yang
+	// butuh aturan khusus.
+	Cond func(op Op, v interface{}) bson.M
+	// Stages membangun stage agregasi untuk field yang butuh koleksi lain.
+	// Stage dijalankan setelah $match.
+	Stages func(op Op, v interface{}) []bson.D
+}
+
+func (f *Field) path() string {
+	if f.Path != "" {
+		return f.Path
+	}
+	return f.Name
+}
+
+func (f *Field) allows(op Op) bool {
+	for _, o := range f.Ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Alias memetakan parameter biasa ke filter field dengan operator tertentu.
+type Alias struct {
+	Field string
+	Op    Op
+}
+
+// Schema mendeskripsikan parameter listing sebuah resource.
+type Schema struct {
+	Fields  []Field
+	Aliases map[string]Alias
+	// Sort adalah field yang boleh dipakai sortBy; nilai lain ditolak
+	// Parse dengan *Error. DefaultSort dipakai bila sortBy kosong.
+	Sort         []string
+	DefaultSort  string
+	DefaultOrder string
+	// Search adalah field yang dicocokkan oleh ?search=. Field Int hanya
+	// dicocokkan bila search berupa angka.
+	Search []string
+	// DefaultLimit dipakai bila ?limit= kosong; limit di atas MaxLimit
+	// diturunkan ke MaxLimit. Nol berarti DefaultLimit dan MaxLimit paket.
+	DefaultLimit int
+	MaxLimit     int
+}
+
+func (s *Schema) field(name string) *Field {
+	for i := range s.Fields {
+		if s.Fields[i].Name == name {
+			return &s.Fields[i]
+		}
+	}
+	return nil
+}
+
+func (s *Schema) limits() (defLimit, maxLimit int) {
+	defLimit, maxLimit = s.DefaultLimit, s.MaxLimit
+	if defLimit == 0 {
+		defLimit = DefaultLimit
+	}
+	if maxLimit == 0 {
+		maxLimit = MaxLimit
+	}
+	return defLimit, maxLimit
+}
+
+func (s *Schema) sortable(name string) bool {
+	for _, f := range s.Sort {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate memastikan Sort, Search, DefaultSort dan Aliases hanya merujuk
+// field yang terdaftar dan batas limit masuk akal.
+func (s *Schema) Validate() error {
+	names := append(append([]string{s.DefaultSort}, s.Sort...), s.Search...)
+	for _, a := range s.Aliases {
+		names = append(names, a.Field)
+	}
+	for _, n := range names {
+		if s.field(n) == nil {
+			return fmt.Errorf("query: unknown field %q in schema", n)
+		}
+	}
+	for param, a := range s.Aliases {
+		if !s.field(a.Field).allows(a.Op) {
+			return fmt.Errorf("query: alias %s uses operator %s not allowed on %s", param, a.Op, a.Field)
+		}
+	}
+	if s.DefaultLimit < 0 || s.MaxLimit < 0 {
+		return fmt.Errorf("query: negative limit in schema")
+	}
+	if defLimit, maxLimit := s.limits(); defLimit > maxLimit {
+		return fmt.Errorf("query: default limit %d exceeds max limit %d", defLimit, maxLimit)
+	}
+	return nil
+}
+
+// MustSchema mengembalikan s, atau panic bila s tidak valid. Untuk skema
+// yang didefinisikan sebagai variabel paket.
+func MustSchema(s *Schema) *Schema {
+	if err := s.Validate(); err != nil {
+		panic(err)
+	}
+	return s
+}
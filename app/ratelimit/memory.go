@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// MemoryStore menyimpan bucket di memori proses. Cocok untuk satu instance.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	idleTTL time.Duration
+	now     func() time.Time // diganti di test
+}
+
+// NewMemoryStore membuat store; bucket yang tidak dipakai selama idleTTL
+// dibuang oleh Run.
+func NewMemoryStore(idleTTL time.Duration) *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, idleTTL: idleTTL, now: time.Now}
+}
+
+func (m *MemoryStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: p.capacity(), last: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = refill(b.tokens, now.Sub(b.last), p)
+	b.last = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return result(b.tokens, allowed, p), nil
+}
+
+// Run membersihkan bucket lama secara berkala sampai ctx selesai.
+func (m *MemoryStore) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.idleTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			m.mu.Lock()
+			for key, b := range m.buckets {
+				if now.Sub(b.last) > m.idleTTL {
+					delete(m.buckets, key)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
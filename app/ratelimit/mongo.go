@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection tempat MongoStore menyimpan bucket. Dokumen lama dihapus oleh
+// TTL index pada expires_at (lihat database.EnsureIndexes).
+const Collection = "rate_limits"
+
+// MongoStore menyimpan bucket di Mongo agar limit berlaku lintas instance.
+// Isi ulang dan pengambilan token dilakukan dalam satu update atomik memakai
+// jam server ($$NOW), sehingga selisih jam antar instance tidak berpengaruh.
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+func NewMongoStore(db *mongo.Database) *MongoStore {
+	return &MongoStore{collection: db.Collection(Collection)}
+}
+
+func (m *MongoStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+	pipeline := takePipeline(p)
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var doc struct {
+		Tokens  float64 `bson:"tokens"`
+		Allowed bool    `bson:"allowed"`
+	}
+	err := m.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&doc)
+	if mongo.IsDuplicateKeyError(err) {
+		// Dua request pertama untuk key yang sama bisa sama-sama upsert;
+		// yang kalah cukup mengulang sekali.
+		err = m.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&doc)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return result(doc.Tokens, doc.Allowed, p), nil
+}
+
+// takePipeline adalah update pipeline yang mengisi ulang bucket sesuai
+// waktu sejak updated_at lalu mengambil satu token jika tersedia.
+func takePipeline(p Policy) mongo.Pipeline {
+	capacity, rate := p.capacity(), p.rate()
+	elapsedSeconds := bson.D{{Key: "$divide", Value: bson.A{
+		bson.D{{Key: "$subtract", Value: bson.A{"$$NOW", bson.D{{Key: "$ifNull", Value: bson.A{"$updated_at", "$$NOW"}}}}}},
+		1000,
+	}}}
+	fullRefillMillis := capacity / rate * 1000
+
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "tokens", Value: bson.D{{Key: "$min", Value: bson.A{
+				capacity,
+				bson.D{{Key: "$add", Value: bson.A{
+					bson.D{{Key: "$ifNull", Value: bson.A{"$tokens", capacity}}},
+					bson.D{{Key: "$multiply", Value: bson.A{elapsedSeconds, rate}}},
+				}}},
+			}}}},
+			{Key: "updated_at", Value: "$$NOW"},
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "allowed", Value: bson.D{{Key: "$gte", Value: bson.A{"$tokens", 1}}}},
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "tokens", Value: bson.D{{Key: "$cond", Value: bson.A{
+				"$allowed",
+				bson.D{{Key: "$subtract", Value: bson.A{"$tokens", 1}}},
+				"$tokens",
+			}}}},
+			{Key: "expires_at", Value: bson.D{{Key: "$add", Value: bson.A{"$$NOW", fullRefillMillis}}}},
+		}}},
+	}
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Jenis key yang dipakai untuk mengelompokkan request.
+const (
+	KeyIP     = "ip"
+	KeyUser   = "user"
+	KeyAPIKey = "api_key"
+)
+
+// Policy adalah token bucket: Requests per Window dengan kapasitas Burst.
+type Policy struct {
+	Name     string
+	Requests int
+	Window   time.Duration
+	Burst    int
+	Key      string
+}
+
+// rate mengembalikan jumlah token yang terisi per detik.
+func (p Policy) rate() float64 {
+	return float64(p.Requests) / p.Window.Seconds()
+}
+
+func (p Policy) capacity() float64 {
+	if p.Burst > 0 {
+		return float64(p.Burst)
+	}
+	return float64(p.Requests)
+}
+
+// Result adalah hasil satu pengambilan token.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // kapan request berikutnya boleh (jika ditolak)
+	Reset      time.Duration // kapan bucket penuh lagi
+}
+
+// Store menyimpan state bucket. MemoryStore untuk satu instance,
+// MongoStore agar beberapa instance berbagi limit yang sama.
+type Store interface {
+	Take(ctx context.Context, key string, p Policy) (Result, error)
+}
+
+// Limiter memilih policy berdasarkan nama lalu mengambil token dari store.
+// TrustProxy mengizinkan IP client diambil dari X-Forwarded-For; aktifkan
+// hanya jika service berada di belakang reverse proxy yang menimpa header itu.
+type Limiter struct {
+	TrustProxy bool
+
+	store    Store
+	policies map[string]Policy
+}
+
+func NewLimiter(store Store, policies ...Policy) (*Limiter, error) {
+	l := &Limiter{store: store, policies: map[string]Policy{}}
+	for _, p := range policies {
+		if p.Requests < 1 || p.Window <= 0 {
+			return nil, fmt.Errorf("rate limit policy %s: requests and window must be positive", p.Name)
+		}
+		switch p.Key {
+		case KeyIP, KeyUser, KeyAPIKey:
+		default:
+			return nil, fmt.Errorf("rate limit policy %s: unknown key %q", p.Name, p.Key)
+		}
+		l.policies[p.Name] = p
+	}
+	return l, nil
+}
+
+// Policy mengembalikan policy dengan nama tersebut.
+func (l *Limiter) Policy(name string) (Policy, bool) {
+	p, ok := l.policies[name]
+	return p, ok
+}
+
+// Allow mengambil satu token untuk key pada policy p.
+func (l *Limiter) Allow(ctx context.Context, p Policy, key string) (Result, error) {
+	return l.store.Take(ctx, p.Name+":"+p.Key+":"+key, p)
+}
+
+// refill menghitung isi bucket setelah elapsed berlalu, dibatasi kapasitas.
+func refill(tokens float64, elapsed time.Duration, p Policy) float64 {
+	return math.Min(p.capacity(), tokens+elapsed.Seconds()*p.rate())
+}
+
+// result menyusun Result dari sisa token setelah pengambilan.
+func result(tokens float64, allowed bool, p Policy) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     int(p.capacity()),
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((p.capacity() - tokens) / p.rate()),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / p.rate())
+	}
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	if s < 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
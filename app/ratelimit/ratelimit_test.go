@@ -0,0 +1,203 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// testPolicy: 6 request per menit (1 token tiap 10 detik), burst 3.
+var testPolicy = Policy{Name: "test", Requests: 6, Window: time.Minute, Burst: 3, Key: KeyIP}
+
+// refillSteps dipakai untuk MemoryStore dan pipeline MongoStore agar
+// keduanya terbukti menghitung bucket dengan cara yang sama.
+var refillSteps = []struct {
+	advance    time.Duration
+	allowed    bool
+	remaining  int
+	retryAfter time.Duration
+	reset      time.Duration
+}{
+	{0, true, 2, 0, 10 * time.Second},
+	{0, true, 1, 0, 20 * time.Second},
+	{0, true, 0, 0, 30 * time.Second},
+	{0, false, 0, 10 * time.Second, 30 * time.Second},
+	{5 * time.Second, false, 0, 5 * time.Second, 25 * time.Second},
+	{5 * time.Second, true, 0, 0, 30 * time.Second},
+	{15 * time.Second, true, 0, 0, 25 * time.Second},
+	// lama tidak dipakai: terisi penuh tapi tidak melebihi burst
+	{time.Hour, true, 2, 0, 10 * time.Second},
+}
+
+func checkStep(t *testing.T, i int, got Result, allowed bool, remaining int, retryAfter, reset time.Duration) {
+	t.Helper()
+	round := func(d time.Duration) time.Duration { return d.Round(time.Millisecond) }
+	if got.Allowed != allowed || got.Remaining != remaining || got.Limit != 3 ||
+		round(got.RetryAfter) != retryAfter || round(got.Reset) != reset {
+		t.Errorf("step %d: got %+v, want allowed=%v remaining=%d retryAfter=%v reset=%v",
+			i, got, allowed, remaining, retryAfter, reset)
+	}
+}
+
+func TestMemoryStoreRefill(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMemoryStore(time.Minute)
+	m.now = func() time.Time { return now }
+
+	for i, step := range refillSteps {
+		now = now.Add(step.advance)
+		res, err := m.Take(context.Background(), "ip:1.2.3.4", testPolicy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkStep(t, i, res, step.allowed, step.remaining, step.retryAfter, step.reset)
+	}
+}
+
+func TestMemoryStoreKeysAreIndependent(t *testing.T) {
+	m := NewMemoryStore(time.Minute)
+	p := Policy{Name: "one", Requests: 1, Window: time.Minute, Key: KeyIP}
+	for _, key := range []string{"a", "b"} {
+		if res, _ := m.Take(context.Background(), key, p); !res.Allowed {
+			t.Errorf("first request for %s was limited", key)
+		}
+	}
+	if res, _ := m.Take(context.Background(), "a", p); res.Allowed {
+		t.Error("second request for a was allowed")
+	}
+}
+
+func TestMongoPipelineRefill(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	doc := bson.M{"_id": "ip:1.2.3.4"} // dokumen hasil upsert pertama
+
+	for i, step := range refillSteps {
+		now = now.Add(step.advance)
+		doc = evalPipeline(t, takePipeline(testPolicy), doc, now)
+		checkStep(t, i, result(doc["tokens"].(float64), doc["allowed"].(bool), testPolicy),
+			step.allowed, step.remaining, step.retryAfter, step.reset)
+
+		if doc["updated_at"] != now {
+			t.Errorf("step %d: updated_at = %v, want server time %v", i, doc["updated_at"], now)
+		}
+		// dokumen baru boleh dihapus TTL index setelah bucket pasti penuh lagi
+		if want := now.Add(30 * time.Second); doc["expires_at"] != want {
+			t.Errorf("step %d: expires_at = %v, want %v", i, doc["expires_at"], want)
+		}
+	}
+}
+
+// evalPipeline menjalankan update pipeline pada doc dengan $$NOW = now.
+// Hanya operator yang dipakai takePipeline yang didukung.
+func evalPipeline(t *testing.T, pipeline mongo.Pipeline, doc bson.M, now time.Time) bson.M {
+	t.Helper()
+	for _, stage := range pipeline {
+		if len(stage) != 1 || stage[0].Key != "$set" {
+			t.Fatalf("unsupported stage %v", stage)
+		}
+		// semua field di satu $set dihitung dari dokumen sebelum stage
+		out := bson.M{}
+		for k, v := range doc {
+			out[k] = v
+		}
+		for _, f := range stage[0].Value.(bson.D) {
+			out[f.Key] = evalExpr(t, f.Value, doc, now)
+		}
+		doc = out
+	}
+	return doc
+}
+
+func evalExpr(t *testing.T, expr interface{}, doc bson.M, now time.Time) interface{} {
+	t.Helper()
+	switch e := expr.(type) {
+	case string:
+		if e == "$$NOW" {
+			return now
+		}
+		if strings.HasPrefix(e, "$") {
+			return doc[e[1:]]
+		}
+		return e
+	case bson.D:
+		args := e[0].Value.(bson.A)
+		arg := func(i int) interface{} { return evalExpr(t, args[i], doc, now) }
+		num := func(i int) float64 {
+			switch v := arg(i).(type) {
+			case float64:
+				return v
+			case int:
+				return float64(v)
+			}
+			t.Fatalf("%s: argument %d is not a number: %v", e[0].Key, i, arg(i))
+			return 0
+		}
+		switch e[0].Key {
+		case "$ifNull":
+			if v := arg(0); v != nil {
+				return v
+			}
+			return arg(1)
+		case "$cond":
+			if arg(0).(bool) {
+				return arg(1)
+			}
+			return arg(2)
+		case "$add":
+			if at, ok := arg(0).(time.Time); ok {
+				return at.Add(time.Duration(num(1) * float64(time.Millisecond)))
+			}
+			return num(0) + num(1)
+		case "$subtract":
+			if at, ok := arg(0).(time.Time); ok {
+				return float64(at.Sub(arg(1).(time.Time)).Milliseconds())
+			}
+			return num(0) - num(1)
+		case "$multiply":
+			return num(0) * num(1)
+		case "$divide":
+			return num(0) / num(1)
+		case "$min":
+			return math.Min(num(0), num(1))
+		case "$gte":
+			return num(0) >= num(1)
+		}
+		t.Fatalf("unsupported operator %s", e[0].Key)
+	}
+	return expr
+}
+
+func TestNewLimiterValidatesPolicies(t *testing.T) {
+	tests := []Policy{
+		{Name: "zero", Requests: 0, Window: time.Minute, Key: KeyIP},
+		{Name: "nowindow", Requests: 1, Key: KeyIP},
+		{Name: "badkey", Requests: 1, Window: time.Minute, Key: "cookie"},
+	}
+	for _, p := range tests {
+		if _, err := NewLimiter(NewMemoryStore(time.Minute), p); err == nil {
+			t.Errorf("NewLimiter(%s) succeeded, want error", p.Name)
+		}
+	}
+}
+
+func TestLimiterSeparatesPolicies(t *testing.T) {
+	a := Policy{Name: "a", Requests: 1, Window: time.Minute, Key: KeyIP}
+	b := Policy{Name: "b", Requests: 1, Window: time.Minute, Key: KeyIP}
+	l, err := NewLimiter(NewMemoryStore(time.Minute), a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []Policy{a, b} {
+		if res, _ := l.Allow(context.Background(), p, "ip:1.2.3.4"); !res.Allowed {
+			t.Errorf("policy %s shares a bucket with another policy", p.Name)
+		}
+	}
+	if _, ok := l.Policy("missing"); ok {
+		t.Error("Policy(missing) found a policy")
+	}
+}
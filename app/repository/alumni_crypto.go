@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"crud-app/app/fieldcrypt"
+	"crud-app/app/models"
+	"crud-app/app/query"
+	"fmt"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	encryptionField = "encryption"
+	emailIndexField = "email_bidx"
+)
+
+// contactFields mengembalikan field kontak alumni yang dienkripsi at rest,
+// per nama field di dokumen.
+func contactFields(a *models.Alumni) map[string]*string {
+	return map[string]*string{
+		"email":      &a.Email,
+		"no_telepon": &a.No_telp,
+		"alamat":     &a.Alamat,
+	}
+}
+
+// contactProjection mengembalikan projection kontak beserta data key dan
+// field extra.
+func contactProjection(extra ...string) bson.M {
+	p := bson.M{encryptionField: 1}
+	for field := range contactFields(&models.Alumni{}) {
+		p[field] = 1
+	}
+	for _, f := range extra {
+		p[f] = 1
+	}
+	return p
+}
+
+// contactAAD mengikat ciphertext ke field dan dokumennya.
+func contactAAD(a *models.Alumni, field string) string {
+	return field + ":" + a.ID.Hex()
+}
+
+// sealAlumni mengenkripsi kontak a (yang masih plaintext) dan mengisi
+// blind index email. Data key a dipakai ulang bila ada; dokumen baru atau
+// yang belum terenkripsi mendapat data key baru dari key aktif.
+func sealAlumni(keys *fieldcrypt.Keyring, a *models.Alumni) error {
+	var dk *fieldcrypt.DataKey
+	var err error
+	if a.Encryption != nil {
+		dk, err = keys.OpenDataKey(a.Encryption.KeyID, a.Encryption.DEK)
+	} else {
+		var id string
+		var wrapped []byte
+		dk, id, wrapped, err = keys.NewDataKey()
+		a.Encryption = &models.FieldEncryption{KeyID: id, DEK: wrapped}
+	}
+	if err != nil {
+		return err
+	}
+
+	a.EmailIndex = keys.BlindIndex(a.Email)
+	for field, v := range contactFields(a) {
+		if *v, err = dk.Encrypt(*v, contactAAD(a, field)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openAlumni mendekripsi kontak a. Dokumen lama tanpa data key dibiarkan
+// karena kontaknya masih plaintext.
+func openAlumni(keys *fieldcrypt.Keyring, a *models.Alumni) error {
+	if a.Encryption == nil {
+		return nil
+	}
+	dk, err := keys.OpenDataKey(a.Encryption.KeyID, a.Encryption.DEK)
+	if err != nil {
+		return err
+	}
+	for field, v := range contactFields(a) {
+		if *v, err = dk.Decrypt(*v, contactAAD(a, field)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contactSet adalah $set untuk kontak a yang sudah di-seal.
+func contactSet(a *models.Alumni) bson.M {
+	set := bson.M{encryptionField: a.Encryption, emailIndexField: a.EmailIndex}
+	for field, v := range contactFields(a) {
+		set[field] = *v
+	}
+	return set
+}
+
+// withEnvelope menambahkan data key ke projection fields agar kontak tetap
+// bisa didekripsi. nil berarti dokumen utuh.
+func withEnvelope(fields []string) []string {
+	if fields == nil {
+		return nil
+	}
+	return append(fields[:len(fields):len(fields)], encryptionField)
+}
+
+// prepare menyesuaikan q dengan penyimpanan terenkripsi: filter email
+// dicocokkan lewat blind index dan projection menyertakan data key.
+func (r *alumniMongo) prepare(q query.Query) query.Query {
+	conds := make([]query.Condition, len(q.Conditions))
+	for i, c := range q.Conditions {
+		if c.Field.Name == "email" {
+			c.Value = r.blindIndex(c.Value)
+		}
+		conds[i] = c
+	}
+	q.Conditions = conds
+	q.Fields = withEnvelope(q.Fields)
+	return q
+}
+
+func (r *alumniMongo) blindIndex(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return r.keys.BlindIndex(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, s := range v {
+			out[i] = r.blindIndex(s)
+		}
+		return out
+	}
+	return v
+}
+
+// rotateAttempts membatasi berapa kali dokumen yang berubah selama rotasi
+// dibaca ulang dan dicoba lagi.
+const rotateAttempts = 5
+
+// rotationStore adalah operasi penyimpanan yang dipakai rotasi key. Dipisah
+// dari alumniMongo agar alur retry bisa diuji tanpa Mongo.
+type rotationStore interface {
+	// eachPending memanggil fn untuk setiap dokumen yang perlu dirotasi:
+	// semua dokumen jika all, selain itu yang belum memakai key aktif.
+	// ids tidak nil membatasi ke dokumen tersebut.
+	eachPending(ctx context.Context, all bool, ids []primitive.ObjectID, fn func(a *models.Alumni) error) error
+	// swapContact menulis kontak after hanya jika dokumen masih sama dengan
+	// before; false berarti dokumen diubah penulis lain sejak dibaca.
+	swapContact(ctx context.Context, before, after *models.Alumni) (bool, error)
+	// countPending menghitung dokumen yang belum memakai key aktif.
+	countPending(ctx context.Context) (int64, error)
+}
+
+// RotateKeys mengenkripsi ulang kontak alumni dengan data key baru dari key
+// aktif. Tanpa all, hanya dokumen yang belum memakai key aktif (termasuk
+// dokumen plaintext lama). Dokumen yang berubah selama rotasi dibaca ulang
+// dan dicoba lagi. Jika setelah itu masih ada dokumen yang belum memakai key
+// aktif, error membungkus ErrRotationIncomplete beserta jumlahnya; key lama
+// baru aman dihapus bila RotateKeys berhasil.
+func (r *alumniMongo) RotateKeys(ctx context.Context, all bool) (int, error) {
+	return rotateKeys(ctx, r, r.keys, all)
+}
+
+func rotateKeys(ctx context.Context, store rotationStore, keys *fieldcrypt.Keyring, all bool) (int, error) {
+	done := 0
+	var ids []primitive.ObjectID
+	for attempt := 0; attempt < rotateAttempts; attempt++ {
+		var changed []primitive.ObjectID
+		err := store.eachPending(ctx, all, ids, func(a *models.Alumni) error {
+			before := *a
+			if err := rekeyAlumni(keys, a); err != nil {
+				return fmt.Errorf("alumni %s: %w", a.ID.Hex(), err)
+			}
+			ok, err := store.swapContact(ctx, &before, a)
+			switch {
+			case err != nil:
+				return err
+			case ok:
+				done++
+			default:
+				changed = append(changed, a.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			return done, err
+		}
+		if len(changed) == 0 {
+			break
+		}
+		slog.Info("alumni changed during key rotation, retrying", "documents", len(changed), "attempt", attempt+1)
+		ids = changed
+	}
+
+	remaining, err := store.countPending(ctx)
+	if err != nil {
+		return done, err
+	}
+	if remaining > 0 {
+		return done, fmt.Errorf("%w: %d alumni documents are not on key %s yet", ErrRotationIncomplete, remaining, keys.ActiveKeyID())
+	}
+	return done, nil
+}
+
+// rekeyAlumni membuka kontak a yang sudah di-seal (atau plaintext lama) lalu
+// men-seal ulang dengan data key baru dari key aktif.
+func rekeyAlumni(keys *fieldcrypt.Keyring, a *models.Alumni) error {
+	if err := openAlumni(keys, a); err != nil {
+		return err
+	}
+	a.Encryption = nil
+	return sealAlumni(keys, a)
+}
+
+func (r *alumniMongo) pendingFilter() bson.M {
+	return bson.M{encryptionField + ".key_id": bson.M{"$ne": r.keys.ActiveKeyID()}}
+}
+
+func (r *alumniMongo) eachPending(ctx context.Context, all bool, ids []primitive.ObjectID, fn func(a *models.Alumni) error) error {
+	filter := bson.M{}
+	if !all {
+		filter = r.pendingFilter()
+	}
+	if ids != nil {
+		filter["_id"] = bson.M{"$in": ids}
+	}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(contactProjection("updated_at")))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var a models.Alumni
+		if err := cursor.Decode(&a); err != nil {
+			return err
+		}
+		if err := fn(&a); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (r *alumniMongo) swapContact(ctx context.Context, before, after *models.Alumni) (bool, error) {
+	ctx, end := r.timeouts.start(ctx, "alumni.RotateKeys")
+	defer end()
+
+	res, err := r.collection.UpdateOne(ctx, unchangedFilter(before), bson.M{"$set": contactSet(after)})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (r *alumniMongo) countPending(ctx context.Context) (int64, error) {
+	ctx, end := r.timeouts.start(ctx, "alumni.RotateKeys")
+	defer end()
+	return r.collection.CountDocuments(ctx, r.pendingFilter())
+}
+
+// unchangedFilter mencocokkan dokumen a hanya jika belum diubah sejak dibaca.
+// Yang dibandingkan adalah nilai skalar (key id, ciphertext kontak,
+// updated_at), bukan subdokumen encryption utuh, agar perbedaan urutan field
+// atau subtype binary tidak membuat dokumen terlewat. Field yang kosong saat
+// dibaca juga cocok dengan field yang tidak ada.
+func unchangedFilter(a *models.Alumni) bson.M {
+	filter := bson.M{"_id": a.ID, "updated_at": a.UpdatedAt}
+	if a.UpdatedAt.IsZero() {
+		filter["updated_at"] = bson.M{"$in": bson.A{nil, a.UpdatedAt}}
+	}
+	if a.Encryption == nil {
+		filter[encryptionField] = nil
+	} else {
+		filter[encryptionField+".key_id"] = a.Encryption.KeyID
+	}
+	for field, v := range contactFields(a) {
+		if *v == "" {
+			filter[field] = bson.M{"$in": bson.A{nil, ""}}
+		} else {
+			filter[field] = *v
+		}
+	}
+	return filter
+}
@@ -0,0 +1,414 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crud-app/app/fieldcrypt"
+	"crud-app/app/models"
+	"crud-app/app/query"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testKeyring(t *testing.T, active string, ids ...string) *fieldcrypt.Keyring {
+	t.Helper()
+	keys := map[string]string{}
+	for _, id := range ids {
+		// key yang sama untuk id yang sama di setiap keyring
+		keys[id] = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte(id[len(id)-1:]), fieldcrypt.KeySize))
+	}
+	index := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{0xbb}, fieldcrypt.KeySize))
+	k, err := fieldcrypt.ParseKeyring(keys, active, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func testAlumni() models.Alumni {
+	return models.Alumni{
+		ID:      primitive.NewObjectID(),
+		Nama:    "Budi",
+		Email:   "Budi@Example.com",
+		No_telp: "0812-3456-7890",
+		Alamat:  "Jl. Merdeka 1",
+	}
+}
+
+func TestSealOpenAlumni(t *testing.T) {
+	keys := testKeyring(t, "k1", "k1")
+	plain := testAlumni()
+	a := plain
+	if err := sealAlumni(keys, &a); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Encryption == nil || a.Encryption.KeyID != "k1" || len(a.Encryption.DEK) == 0 {
+		t.Fatalf("encryption = %+v", a.Encryption)
+	}
+	for field, v := range contactFields(&a) {
+		if !fieldcrypt.IsEncrypted(*v) {
+			t.Errorf("%s stored as %q", field, *v)
+		}
+	}
+	if a.Nama != plain.Nama {
+		t.Errorf("nama changed to %q", a.Nama)
+	}
+	if a.EmailIndex != keys.BlindIndex("budi@example.com") {
+		t.Errorf("email_bidx = %q", a.EmailIndex)
+	}
+
+	// bentuk yang disimpan ke Mongo juga harus bisa dibaca kembali
+	raw, err := bson.Marshal(&a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored models.Alumni
+	if err := bson.Unmarshal(raw, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := openAlumni(keys, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.Email != plain.Email || stored.No_telp != plain.No_telp || stored.Alamat != plain.Alamat {
+		t.Errorf("opened = %q %q %q", stored.Email, stored.No_telp, stored.Alamat)
+	}
+}
+
+func TestOpenAlumniRejectsSwappedCiphertext(t *testing.T) {
+	keys := testKeyring(t, "k1", "k1")
+	a := testAlumni()
+	if err := sealAlumni(keys, &a); err != nil {
+		t.Fatal(err)
+	}
+
+	// dokumen lain dengan data key yang sama tetap tidak bisa membuka kontak a
+	moved := a
+	moved.ID = primitive.NewObjectID()
+	if err := openAlumni(keys, &moved); err == nil {
+		t.Error("ciphertext opened under another document id")
+	}
+
+	swapped := a
+	swapped.Email, swapped.Alamat = a.Alamat, a.Email
+	if err := openAlumni(keys, &swapped); err == nil {
+		t.Error("ciphertext opened under another field")
+	}
+}
+
+func TestOpenAlumniLegacyPlaintext(t *testing.T) {
+	keys := testKeyring(t, "k1", "k1")
+	a := testAlumni()
+	if err := openAlumni(keys, &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Email != "Budi@Example.com" {
+		t.Errorf("legacy email = %q", a.Email)
+	}
+}
+
+func TestRekeyAlumniAfterRotation(t *testing.T) {
+	plain := testAlumni()
+	a := plain
+	if err := sealAlumni(testKeyring(t, "k1", "k1"), &a); err != nil {
+		t.Fatal(err)
+	}
+	oldDEK := a.Encryption.DEK
+	oldEmail := a.Email
+
+	// k2 aktif, k1 masih ada untuk membaca dokumen lama
+	rotated := testKeyring(t, "k2", "k1", "k2")
+	old := a
+	if err := openAlumni(rotated, &old); err != nil || old.Email != plain.Email {
+		t.Fatalf("open with old key: %q, %v", old.Email, err)
+	}
+
+	if err := rekeyAlumni(rotated, &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Encryption.KeyID != "k2" || bytes.Equal(a.Encryption.DEK, oldDEK) {
+		t.Errorf("encryption after rekey = %+v", a.Encryption)
+	}
+	if a.Email == oldEmail || !fieldcrypt.IsEncrypted(a.Email) {
+		t.Errorf("email not re-encrypted: %q", a.Email)
+	}
+	if a.EmailIndex != rotated.BlindIndex(plain.Email) {
+		t.Errorf("email_bidx changed by rotation")
+	}
+
+	// setelah rotasi k1 boleh dihapus
+	only := testKeyring(t, "k2", "k2")
+	if err := openAlumni(only, &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Email != plain.Email || a.No_telp != plain.No_telp || a.Alamat != plain.Alamat {
+		t.Errorf("after rotation = %q %q %q", a.Email, a.No_telp, a.Alamat)
+	}
+}
+
+func TestRekeyAlumniEncryptsLegacyPlaintext(t *testing.T) {
+	keys := testKeyring(t, "k1", "k1")
+	a := testAlumni()
+	if err := rekeyAlumni(keys, &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Encryption == nil || !fieldcrypt.IsEncrypted(a.No_telp) || a.EmailIndex == "" {
+		t.Errorf("legacy document not encrypted: %+v", a)
+	}
+}
+
+func TestPrepareUsesEmailBlindIndex(t *testing.T) {
+	keys := testKeyring(t, "k1", "k1")
+	r := &alumniMongo{keys: keys}
+	q, err := query.Parse(url.Values{"email": {"Budi@Example.com, siti@example.com"}}, AlumniSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Fields = []string{"id", "email"}
+
+	p := r.prepare(q)
+	want := bson.M{emailIndexField: bson.M{"$in": []interface{}{
+		keys.BlindIndex("budi@example.com"),
+		keys.BlindIndex("siti@example.com"),
+	}}}
+	if got := p.Match(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Match() = %v, want %v", got, want)
+	}
+	if proj := p.Projection(); proj[encryptionField] != 1 {
+		t.Errorf("projection %v lacks %s", proj, encryptionField)
+	}
+	if len(q.Fields) != 2 || q.Conditions[0].Value.([]interface{})[0] != "Budi@Example.com" {
+		t.Error("prepare modified the caller's query")
+	}
+}
+
+// memRotationStore adalah rotationStore di memori. beforeSwap dipanggil
+// sebelum dokumen dibandingkan, untuk mensimulasikan penulis lain yang
+// mengubah dokumen di tengah rotasi.
+type memRotationStore struct {
+	keys       *fieldcrypt.Keyring
+	docs       map[primitive.ObjectID]models.Alumni
+	order      []primitive.ObjectID
+	beforeSwap func(id primitive.ObjectID)
+}
+
+func newMemRotationStore(t *testing.T, keys *fieldcrypt.Keyring, docs ...models.Alumni) *memRotationStore {
+	t.Helper()
+	s := &memRotationStore{keys: keys, docs: map[primitive.ObjectID]models.Alumni{}}
+	for _, a := range docs {
+		s.docs[a.ID] = a
+		s.order = append(s.order, a.ID)
+	}
+	return s
+}
+
+func (s *memRotationStore) pending(a models.Alumni) bool {
+	return a.Encryption == nil || a.Encryption.KeyID != s.keys.ActiveKeyID()
+}
+
+func (s *memRotationStore) eachPending(_ context.Context, all bool, ids []primitive.ObjectID, fn func(a *models.Alumni) error) error {
+	only := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		only[id] = true
+	}
+	for _, id := range s.order {
+		a, ok := s.docs[id]
+		if !ok || (ids != nil && !only[id]) || (!all && !s.pending(a)) {
+			continue
+		}
+		if err := fn(&a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memRotationStore) swapContact(_ context.Context, before, after *models.Alumni) (bool, error) {
+	if s.beforeSwap != nil {
+		s.beforeSwap(before.ID)
+	}
+	cur, ok := s.docs[before.ID]
+	if !ok || !reflect.DeepEqual(cur, *before) {
+		return false, nil
+	}
+	cur.Encryption, cur.EmailIndex = after.Encryption, after.EmailIndex
+	cur.Email, cur.No_telp, cur.Alamat = after.Email, after.No_telp, after.Alamat
+	s.docs[before.ID] = cur
+	return true, nil
+}
+
+func (s *memRotationStore) countPending(context.Context) (int64, error) {
+	var n int64
+	for _, a := range s.docs {
+		if s.pending(a) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// sealedAlumni membuat n alumni yang kontaknya di-seal dengan keys.
+func sealedAlumni(t *testing.T, keys *fieldcrypt.Keyring, n int) []models.Alumni {
+	t.Helper()
+	var out []models.Alumni
+	for i := 0; i < n; i++ {
+		a := testAlumni()
+		a.UpdatedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		if err := sealAlumni(keys, &a); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func TestRotateKeysRetriesDocumentsChangedDuringRotation(t *testing.T) {
+	old := testKeyring(t, "k1", "k1")
+	rotated := testKeyring(t, "k2", "k1", "k2")
+	docs := sealedAlumni(t, old, 3)
+	store := newMemRotationStore(t, rotated, docs...)
+
+	privacyID, contactID := docs[0].ID, docs[1].ID
+	swaps := map[primitive.ObjectID]int{}
+	store.beforeSwap = func(id primitive.ObjectID) {
+		swaps[id]++
+		if swaps[id] > 1 {
+			return
+		}
+		a := store.docs[id]
+		a.UpdatedAt = a.UpdatedAt.Add(time.Second)
+		switch id {
+		case privacyID:
+			// seperti UpdatePrivacy: kontak dan key tidak berubah
+			a.Privacy.ShowEmail = true
+		case contactID:
+			// seperti Update: kontak baru di-seal dengan data key lama
+			if err := openAlumni(rotated, &a); err != nil {
+				t.Fatal(err)
+			}
+			a.Email = "baru@example.com"
+			if err := sealAlumni(rotated, &a); err != nil {
+				t.Fatal(err)
+			}
+		}
+		store.docs[id] = a
+	}
+
+	n, err := rotateKeys(context.Background(), store, rotated, false)
+	if err != nil {
+		t.Fatalf("rotateKeys: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("rotated %d documents, want 3", n)
+	}
+
+	// key lama sudah tidak dibutuhkan
+	onlyNew := testKeyring(t, "k2", "k2")
+	for _, id := range store.order {
+		a := store.docs[id]
+		if a.Encryption.KeyID != "k2" {
+			t.Errorf("alumni %s still on key %s", id.Hex(), a.Encryption.KeyID)
+		}
+		if err := openAlumni(onlyNew, &a); err != nil {
+			t.Errorf("alumni %s unreadable without old key: %v", id.Hex(), err)
+		}
+		switch id {
+		case privacyID:
+			if !a.Privacy.ShowEmail {
+				t.Error("privacy change made during rotation was lost")
+			}
+		case contactID:
+			if a.Email != "baru@example.com" {
+				t.Errorf("email = %q, want the value written during rotation", a.Email)
+			}
+		}
+	}
+}
+
+func TestRotateKeysReportsDocumentsLeftOnOldKey(t *testing.T) {
+	old := testKeyring(t, "k1", "k1")
+	rotated := testKeyring(t, "k2", "k1", "k2")
+	docs := sealedAlumni(t, old, 3)
+	store := newMemRotationStore(t, rotated, docs...)
+
+	busy := docs[2].ID
+	store.beforeSwap = func(id primitive.ObjectID) {
+		if id == busy {
+			a := store.docs[id]
+			a.UpdatedAt = a.UpdatedAt.Add(time.Second)
+			store.docs[id] = a
+		}
+	}
+
+	n, err := rotateKeys(context.Background(), store, rotated, false)
+	if !errors.Is(err, ErrRotationIncomplete) {
+		t.Fatalf("err = %v, want ErrRotationIncomplete", err)
+	}
+	if !strings.Contains(err.Error(), "1 alumni documents") {
+		t.Errorf("error %q does not report how many documents are left", err)
+	}
+	if n != 2 {
+		t.Errorf("rotated %d documents, want 2", n)
+	}
+	if store.docs[busy].Encryption.KeyID != "k1" {
+		t.Error("busy document was overwritten")
+	}
+}
+
+func TestRotateKeysAllAndLegacy(t *testing.T) {
+	keys := testKeyring(t, "k1", "k1")
+	docs := sealedAlumni(t, keys, 2)
+	docs = append(docs, testAlumni()) // plaintext lama
+	store := newMemRotationStore(t, keys, docs...)
+
+	if n, err := rotateKeys(context.Background(), store, keys, false); err != nil || n != 1 {
+		t.Errorf("rotateKeys = %d, %v; want only the legacy document", n, err)
+	}
+	before := store.docs[docs[0].ID].Encryption.DEK
+	if n, err := rotateKeys(context.Background(), store, keys, true); err != nil || n != 3 {
+		t.Errorf("rotateKeys(all) = %d, %v; want 3", n, err)
+	}
+	if bytes.Equal(store.docs[docs[0].ID].Encryption.DEK, before) {
+		t.Error("all did not issue a new data key")
+	}
+}
+
+func TestUnchangedFilterComparesScalars(t *testing.T) {
+	keys := testKeyring(t, "k1", "k1")
+	a := sealedAlumni(t, keys, 1)[0]
+	a.Alamat = ""
+
+	f := unchangedFilter(&a)
+	if _, ok := f[encryptionField]; ok {
+		t.Errorf("filter compares the whole %s subdocument: %v", encryptionField, f)
+	}
+	want := bson.M{
+		"_id":                       a.ID,
+		"updated_at":                a.UpdatedAt,
+		encryptionField + ".key_id": "k1",
+		"email":                     a.Email,
+		"no_telepon":                a.No_telp,
+		"alamat":                    bson.M{"$in": bson.A{nil, ""}},
+	}
+	if !reflect.DeepEqual(f, want) {
+		t.Errorf("filter = %v, want %v", f, want)
+	}
+
+	// dokumen plaintext lama tanpa updated_at dan data key
+	legacy := testAlumni()
+	f = unchangedFilter(&legacy)
+	if v, ok := f[encryptionField]; !ok || v != nil {
+		t.Errorf("legacy filter %s = %v, want nil", encryptionField, v)
+	}
+	if !reflect.DeepEqual(f["updated_at"], bson.M{"$in": bson.A{nil, time.Time{}}}) {
+		t.Errorf("legacy filter updated_at = %v", f["updated_at"])
+	}
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"crud-app/app/fieldcrypt"
 	"crud-app/app/models"
+	"crud-app/app/query"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -12,146 +14,200 @@ import (
 )
 
 type AlumniRepository interface {
-	FindByID(id string) (*models.Alumni, error)
-	GetAlumni(search, sortBy, order string, page, limit int) ([]models.Alumni, int, error)
-	Create(a *models.Alumni) error
-	Update(id string, a *models.Alumni) error
-	Delete(id string) error
+	FindByID(ctx context.Context, id string, fields []string) (*models.Alumni, error)
+	GetAlumni(ctx context.Context, q query.Query) ([]models.Alumni, query.Page, error)
+	FindByIDWithPekerjaan(ctx context.Context, id string, fields []string) (*models.AlumniWithPekerjaan, error)
+	GetAlumniWithPekerjaan(ctx context.Context, q query.Query) ([]models.AlumniWithPekerjaan, query.Page, error)
+	Create(ctx context.Context, a *models.Alumni) error
+	Update(ctx context.Context, id string, a *models.Alumni) error
+	Delete(ctx context.Context, id string) error
+	GetPrivacy(ctx context.Context, id string) (*models.PrivacySettings, error)
+	UpdatePrivacy(ctx context.Context, id string, p models.PrivacySettings) error
+	RotateKeys(ctx context.Context, all bool) (int, error)
 }
 
+// alumniMongo menyimpan kontak alumni terenkripsi dengan keys (lihat
+// alumni_crypto.go).
 type alumniMongo struct {
 	collection *mongo.Collection
+	timeouts   Timeouts
+	keys       *fieldcrypt.Keyring
 }
 
-func NewAlumniRepository(db *mongo.Database) AlumniRepository {
+func NewAlumniRepository(db *mongo.Database, timeouts Timeouts, keys *fieldcrypt.Keyring) AlumniRepository {
 	return &alumniMongo{
 		collection: db.Collection("alumni"),
+		timeouts:   timeouts,
+		keys:       keys,
 	}
 }
 
-func (r *alumniMongo) GetAlumni(search, sortBy, order string, page, limit int) ([]models.Alumni, int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *alumniMongo) GetAlumni(ctx context.Context, q query.Query) ([]models.Alumni, query.Page, error) {
+	ctx, end := r.timeouts.start(ctx, "alumni.GetAlumni")
+	defer end()
 
 	var alumni []models.Alumni
-
-	if page < 1 {
-		page = 1
+	page, err := findPage(ctx, r.collection, r.timeouts.listMaxTime(), r.prepare(q), nil, &alumni)
+	if err != nil {
+		return nil, query.Page{}, err
 	}
-	if limit < 1 {
-		limit = 10
+	for i := range alumni {
+		if err := openAlumni(r.keys, &alumni[i]); err != nil {
+			return nil, query.Page{}, err
+		}
 	}
+	return alumni, page, nil
+}
 
-	skip := int64((page - 1) * limit)
+// GetAlumniWithPekerjaan sama dengan GetAlumni, ditambah riwayat pekerjaan
+// aktif tiap alumni lewat $lookup.
+func (r *alumniMongo) GetAlumniWithPekerjaan(ctx context.Context, q query.Query) ([]models.AlumniWithPekerjaan, query.Page, error) {
+	ctx, end := r.timeouts.start(ctx, "alumni.GetAlumniWithPekerjaan")
+	defer end()
 
-	allowedSort := map[string]bool{"_id": true, "nama": true, "angkatan": true, "jurusan": true, "email": true}
-	if !allowedSort[sortBy] {
-		sortBy = "_id"
-	}
-	if order != "asc" && order != "desc" {
-		order = "asc"
+	var alumni []models.AlumniWithPekerjaan
+	page, err := aggregatePage(ctx, r.collection, r.timeouts.listMaxTime(), r.prepare(q), nil, withPekerjaanStages(), &alumni)
+	if err != nil {
+		return nil, query.Page{}, err
 	}
-
-	// Build filter
-	filter := bson.M{}
-	if search != "" {
-		filter = bson.M{
-			"$or": []bson.M{
-				{"nama": bson.M{"$regex": search, "$options": "i"}},
-				{"jurusan": bson.M{"$regex": search, "$options": "i"}},
-				{"angkatan": search},
-				{"email": bson.M{"$regex": search, "$options": "i"}},
-			},
+	for i := range alumni {
+		if err := openAlumni(r.keys, &alumni[i].Alumni); err != nil {
+			return nil, query.Page{}, err
 		}
 	}
+	return alumni, page, nil
+}
+
+func (r *alumniMongo) FindByID(ctx context.Context, id string, fields []string) (*models.Alumni, error) {
+	ctx, end := r.timeouts.start(ctx, "alumni.FindByID")
+	defer end()
 
-	// Count total
-	total, err := r.collection.CountDocuments(ctx, filter)
+	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	// Sort order
-	sortOrder := int32(1)
-	if order == "desc" {
-		sortOrder = -1
+	opts := options.FindOne()
+	if p := query.Projection(withEnvelope(fields)); p != nil {
+		opts.SetProjection(p)
 	}
-
-	// Query with pagination and sorting
-	opts := options.Find().
-		SetSkip(skip).
-		SetLimit(int64(limit)).
-		SetSort(bson.M{sortBy: sortOrder})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	var a models.Alumni
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}, opts).Decode(&a)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-	defer cursor.Close(ctx)
-
-	if err = cursor.All(ctx, &alumni); err != nil {
-		return nil, 0, err
+	if err := openAlumni(r.keys, &a); err != nil {
+		return nil, err
 	}
-
-	return alumni, int(total), nil
+	return &a, nil
 }
 
-func (r *alumniMongo) FindByID(id string) (*models.Alumni, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// FindByIDWithPekerjaan mengambil alumni beserta riwayat pekerjaan aktifnya.
+func (r *alumniMongo) FindByIDWithPekerjaan(ctx context.Context, id string, fields []string) (*models.AlumniWithPekerjaan, error) {
+	ctx, end := r.timeouts.start(ctx, "alumni.FindByIDWithPekerjaan")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
 	}
 
-	var a models.Alumni
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&a)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": objID}}},
+	}
+	if p := query.Projection(withEnvelope(fields)); p != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: p}})
+	}
+	pipeline = append(pipeline, withPekerjaanStages()...)
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+		return nil, mongo.ErrNoDocuments
+	}
+	var a models.AlumniWithPekerjaan
+	if err := cursor.Decode(&a); err != nil {
+		return nil, err
+	}
+	if err := openAlumni(r.keys, &a.Alumni); err != nil {
+		return nil, err
+	}
 	return &a, nil
 }
 
-func (r *alumniMongo) Create(a *models.Alumni) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *alumniMongo) Create(ctx context.Context, a *models.Alumni) error {
+	ctx, end := r.timeouts.start(ctx, "alumni.Create")
+	defer end()
 
 	a.ID = primitive.NewObjectID()
 	a.CreatedAt = time.Now()
 	a.UpdatedAt = time.Now()
 
-	_, err := r.collection.InsertOne(ctx, a)
-	return err
+	// a tetap plaintext untuk pemanggil; yang disimpan salinan terenkripsi
+	sealed := *a
+	if err := sealAlumni(r.keys, &sealed); err != nil {
+		return err
+	}
+	if _, err := r.collection.InsertOne(ctx, &sealed); err != nil {
+		return err
+	}
+	indexSearchTerms(ctx, r.collection, alumniSearchFields, a.ID)
+	return nil
 }
 
-func (r *alumniMongo) Update(id string, a *models.Alumni) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *alumniMongo) Update(ctx context.Context, id string, a *models.Alumni) error {
+	ctx, end := r.timeouts.start(ctx, "alumni.Update")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
+	// kontak dienkripsi bersama dengan data key dokumen; dokumen plaintext
+	// lama sekalian dienkripsi seluruhnya
+	var stored models.Alumni
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}, options.FindOne().SetProjection(contactProjection())).Decode(&stored)
+	if err != nil {
+		return err
+	}
+	if err := openAlumni(r.keys, &stored); err != nil {
+		return err
+	}
+	stored.No_telp = a.No_telp
+	if err := sealAlumni(r.keys, &stored); err != nil {
+		return err
+	}
+
 	a.UpdatedAt = time.Now()
 
+	set := contactSet(&stored)
+	set["nama"] = a.Nama
+	set["jurusan"] = a.Jurusan
+	set["updated_at"] = a.UpdatedAt
 	update := bson.M{
-		"$set": bson.M{
-			"nama":       a.Nama,
-			"jurusan":    a.Jurusan,
-			"no_telepon": a.No_telp,
-			"updated_at": a.UpdatedAt,
-		},
+		"$set": set,
+		// term pencarian dihitung ulang di bawah atau oleh indexer
+		"$unset": bson.M{searchVersionField: ""},
 	}
 
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
-	return err
+	if _, err = r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update); err != nil {
+		return err
+	}
+	indexSearchTerms(ctx, r.collection, alumniSearchFields, objID)
+	return nil
 }
 
-func (r *alumniMongo) Delete(id string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *alumniMongo) Delete(ctx context.Context, id string) error {
+	ctx, end := r.timeouts.start(ctx, "alumni.Delete")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -161,3 +217,41 @@ func (r *alumniMongo) Delete(id string) error {
 	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objID})
 	return err
 }
+
+func (r *alumniMongo) GetPrivacy(ctx context.Context, id string) (*models.PrivacySettings, error) {
+	ctx, end := r.timeouts.start(ctx, "alumni.GetPrivacy")
+	defer end()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var a models.Alumni
+	opts := options.FindOne().SetProjection(bson.M{"privacy": 1})
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objID}, opts).Decode(&a); err != nil {
+		return nil, err
+	}
+	return &a.Privacy, nil
+}
+
+func (r *alumniMongo) UpdatePrivacy(ctx context.Context, id string, p models.PrivacySettings) error {
+	ctx, end := r.timeouts.start(ctx, "alumni.UpdatePrivacy")
+	defer end()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{
+		"$set": bson.M{"privacy": p, "updated_at": time.Now()},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
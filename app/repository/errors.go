@@ -0,0 +1,19 @@
+package repository
+
+import "errors"
+
+// Error yang bisa dikenali oleh service untuk dipetakan ke status HTTP
+// dan pesan yang sesuai.
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrAlreadyRestored  = errors.New("data not found or already restored")
+	ErrNothingToRestore = errors.New("no data found to restore")
+	ErrNotInTrash       = errors.New("data not found or not in trash")
+	ErrTrashEmpty       = errors.New("no data found in trash")
+	// ErrQueryTimeout berarti query dihentikan karena melewati batas waktu
+	// (maxTimeMS atau timeout operasi).
+	ErrQueryTimeout = errors.New("query timed out")
+	// ErrRotationIncomplete berarti masih ada dokumen yang belum memakai key
+	// aktif setelah rotasi, sehingga key lama belum boleh dihapus.
+	ErrRotationIncomplete = errors.New("key rotation incomplete")
+)
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"crud-app/app/query"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// findPage menghitung dokumen yang cocok dengan q (ditambah base), bila
+// q.Count, lalu mengambil satu halaman ke out. Setiap query dibatasi
+// maxTime di sisi server; timeout dikembalikan sebagai ErrQueryTimeout.
+func findPage(ctx context.Context, coll *mongo.Collection, maxTime time.Duration, q query.Query, base bson.M, out interface{}) (query.Page, error) {
+	if len(q.Stages()) > 0 {
+		return aggregatePage(ctx, coll, maxTime, q, base, nil, out)
+	}
+
+	filter := q.Match(base)
+	total := -1
+	if q.Count {
+		n, err := coll.CountDocuments(ctx, filter, options.Count().SetMaxTime(maxTime))
+		if err != nil {
+			return query.Page{}, listError(err)
+		}
+		total = int(n)
+	}
+	if seek := q.Seek(); seek != nil {
+		filter = query.And(filter, seek)
+	}
+
+	// satu dokumen lebih untuk mengetahui ada halaman berikutnya
+	opts := options.Find().
+		SetSkip(q.Skip()).
+		SetLimit(int64(q.Limit + 1)).
+		SetSort(q.Sort()).
+		SetMaxTime(maxTime)
+	if p := q.Projection(); p != nil {
+		opts.SetProjection(p)
+	}
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return query.Page{}, listError(err)
+	}
+	defer cursor.Close(ctx)
+
+	var raws []bson.Raw
+	if err = cursor.All(ctx, &raws); err != nil {
+		return query.Page{}, listError(err)
+	}
+	raws, page := q.Paginate(raws, total)
+	return page, decodeAll(raws, out)
+}
+
+// aggregatePage sama dengan findPage lewat agregasi. Stage extra dijalankan
+// setelah paging, jadi hanya untuk dokumen yang dikirim.
+func aggregatePage(ctx context.Context, coll *mongo.Collection, maxTime time.Duration, q query.Query, base bson.M, extra []bson.D, out interface{}) (query.Page, error) {
+	opts := options.Aggregate().SetMaxTime(maxTime)
+	total := -1
+	if q.Count {
+		n, err := aggregateCount(ctx, coll, q.Pipeline(base), opts)
+		if err != nil {
+			return query.Page{}, listError(err)
+		}
+		total = n
+	}
+
+	pipeline := q.Pipeline(base)
+	if seek := q.Seek(); seek != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: seek}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: q.Sort()}},
+		bson.D{{Key: "$skip", Value: q.Skip()}},
+		bson.D{{Key: "$limit", Value: int64(q.Limit + 1)}},
+	)
+	if p := q.Projection(); p != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: p}})
+	}
+	pipeline = append(pipeline, extra...)
+
+	cursor, err := coll.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return query.Page{}, listError(err)
+	}
+	defer cursor.Close(ctx)
+
+	var raws []bson.Raw
+	if err = cursor.All(ctx, &raws); err != nil {
+		return query.Page{}, listError(err)
+	}
+	raws, page := q.Paginate(raws, total)
+	return page, decodeAll(raws, out)
+}
+
+func aggregateCount(ctx context.Context, coll *mongo.Collection, pipeline mongo.Pipeline, opts *options.AggregateOptions) (int, error) {
+	cursor, err := coll.Aggregate(ctx, append(pipeline, bson.D{{Key: "$count", Value: "total"}}), opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var res []struct {
+		Total int `bson:"total"`
+	}
+	if err = cursor.All(ctx, &res); err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, nil
+	}
+	return res[0].Total, nil
+}
+
+// decodeAll men-decode raws ke out, pointer ke slice.
+func decodeAll(raws []bson.Raw, out interface{}) error {
+	slice := reflect.ValueOf(out).Elem()
+	items := reflect.MakeSlice(slice.Type(), len(raws), len(raws))
+	for i, raw := range raws {
+		if err := bson.Unmarshal(raw, items.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	slice.Set(items)
+	return nil
+}
+
+// listError menandai timeout, baik maxTimeMS dari server maupun batas
+// waktu operasi, sebagai ErrQueryTimeout.
+func listError(err error) error {
+	if mongo.IsTimeout(err) {
+		return fmt.Errorf("%w: %v", ErrQueryTimeout, err)
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// pekerjaanCollection dipakai juga oleh $lookup dari koleksi alumni.
+const pekerjaanCollection = "pekerjaan_alumni"
+
+// noEndDate adalah nilai tanggal_selesai_kerja untuk pekerjaan yang belum
+// selesai: time.Time kosong di Go tersimpan sebagai tanggal tahun 1.
+var noEndDate = time.Time{}
+
+// ongoingExpr adalah ekspresi agregasi "pekerjaan masih berjalan" untuk
+// variabel v (misalnya "$$job"): sudah mulai dan belum selesai, yaitu
+// tanggal selesai kosong atau masih di masa depan.
+func ongoingExpr(v string) bson.D {
+	return bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "$lte", Value: bson.A{v + ".tanggal_mulai_kerja", "$$NOW"}}},
+		bson.D{{Key: "$or", Value: bson.A{
+			// null dan field yang tidak ada juga lebih kecil dari noEndDate
+			bson.D{{Key: "$lte", Value: bson.A{v + ".tanggal_selesai_kerja", noEndDate}}},
+			bson.D{{Key: "$gt", Value: bson.A{v + ".tanggal_selesai_kerja", "$$NOW"}}},
+		}}},
+	}}}
+}
+
+// ongoingFilter sama dengan ongoingExpr untuk query find. Di luar $expr
+// null tidak lebih kecil dari tanggal, jadi perlu dicek terpisah.
+func ongoingFilter(now time.Time) bson.M {
+	return bson.M{
+		"tanggal_mulai_kerja": bson.M{"$lte": now},
+		"$or": []bson.M{
+			{"tanggal_selesai_kerja": nil},
+			{"tanggal_selesai_kerja": bson.M{"$lte": noEndDate}},
+			{"tanggal_selesai_kerja": bson.M{"$gt": now}},
+		},
+	}
+}
+
+// withPekerjaanStages menambahkan field pekerjaan (pekerjaan aktif, terbaru
+// dulu menurut tanggal mulai) dan current_job (pekerjaan berjalan yang
+// paling baru dimulai) ke setiap dokumen alumni.
+func withPekerjaanStages() []bson.D {
+	return []bson.D{
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: pekerjaanCollection},
+			{Key: "let", Value: bson.D{{Key: "alumniId", Value: "$_id"}}},
+			{Key: "pipeline", Value: bson.A{
+				bson.D{{Key: "$match", Value: bson.D{
+					{Key: "$expr", Value: bson.D{{Key: "$eq", Value: bson.A{"$alumni_id", "$$alumniId"}}}},
+					{Key: "is_deleted", Value: nil},
+				}}},
+				bson.D{{Key: "$sort", Value: bson.D{{Key: "tanggal_mulai_kerja", Value: -1}, {Key: "_id", Value: -1}}}},
+			}},
+			{Key: "as", Value: "pekerjaan"},
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "current_job", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{
+				bson.D{{Key: "$filter", Value: bson.D{
+					{Key: "input", Value: "$pekerjaan"},
+					{Key: "as", Value: "job"},
+					{Key: "cond", Value: ongoingExpr("$$job")},
+				}}},
+				0,
+			}}}},
+		}}},
+	}
+}
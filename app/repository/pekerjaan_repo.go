@@ -3,7 +3,7 @@ package repository
 import (
 	"context"
 	"crud-app/app/models"
-	"fmt"
+	"crud-app/app/query"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -13,34 +13,37 @@ import (
 )
 
 type PekerjaanRepository interface {
-	FindByAlumni(alumniID string) ([]models.Pekerjaan, error)
-	Create(p *models.Pekerjaan) error
-	Update(id string, p *models.Pekerjaan) error
-	Delete(id string) error
-	GetPekerjaan(search, sortBy, order string, page, limit int) ([]models.Pekerjaan, int, error)
-	SoftDeleteByAdmin(alumni_ID string) error
-	SoftDeleteByUser(Id string, alumni_id string) error
-	FindByPekerjaanID(id string) (*models.Pekerjaan, error)
-	GetTrash(search, sortBy, order string, page, limit int) ([]models.Pekerjaan, int, error)
-	Restore(pekerjaanID, alumniID string) error
-	RestoreByAdmin(alumniID string) error
-	HardDelete(pekerjaanID, alumniID string) error
-	HardDeleteByAdmin(alumniID string) error
+	FindByAlumni(ctx context.Context, alumniID string) ([]models.Pekerjaan, error)
+	Create(ctx context.Context, p *models.Pekerjaan) error
+	Update(ctx context.Context, id string, p *models.Pekerjaan) error
+	Delete(ctx context.Context, id string) error
+	GetPekerjaan(ctx context.Context, q query.Query) ([]models.Pekerjaan, query.Page, error)
+	SoftDeleteByAdmin(ctx context.Context, alumni_ID string) error
+	SoftDeleteByUser(ctx context.Context, Id string, alumni_id string) error
+	SoftDelete(ctx context.Context, pekerjaanID, alumniID string) error
+	FindByPekerjaanID(ctx context.Context, id string, fields []string) (*models.Pekerjaan, error)
+	GetTrash(ctx context.Context, q query.Query) ([]models.Pekerjaan, query.Page, error)
+	Restore(ctx context.Context, pekerjaanID, alumniID string) error
+	RestoreByAdmin(ctx context.Context, alumniID string) error
+	HardDelete(ctx context.Context, pekerjaanID, alumniID string) error
+	HardDeleteByAdmin(ctx context.Context, alumniID string) error
 }
 
 type pekerjaanMongo struct {
 	collection *mongo.Collection
+	timeouts   Timeouts
 }
 
-func NewPekerjaanRepository(db *mongo.Database) PekerjaanRepository {
+func NewPekerjaanRepository(db *mongo.Database, timeouts Timeouts) PekerjaanRepository {
 	return &pekerjaanMongo{
-		collection: db.Collection("pekerjaan_alumni"),
+		collection: db.Collection(pekerjaanCollection),
+		timeouts:   timeouts,
 	}
 }
 
-func (r *pekerjaanMongo) FindByAlumni(alumniID string) ([]models.Pekerjaan, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) FindByAlumni(ctx context.Context, alumniID string) ([]models.Pekerjaan, error) {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.FindByAlumni")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(alumniID)
 	if err != nil {
@@ -66,38 +69,45 @@ func (r *pekerjaanMongo) FindByAlumni(alumniID string) ([]models.Pekerjaan, erro
 	return list, nil
 }
 
-func (r *pekerjaanMongo) FindByPekerjaanID(id string) (*models.Pekerjaan, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) FindByPekerjaanID(ctx context.Context, id string, fields []string) (*models.Pekerjaan, error) {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.FindByPekerjaanID")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
 	}
 
+	opts := options.FindOne()
+	if proj := query.Projection(fields); proj != nil {
+		opts.SetProjection(proj)
+	}
 	var p models.Pekerjaan
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&p)
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}, opts).Decode(&p)
 	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
-func (r *pekerjaanMongo) Create(p *models.Pekerjaan) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) Create(ctx context.Context, p *models.Pekerjaan) error {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.Create")
+	defer end()
 
 	p.ID = primitive.NewObjectID()
 	p.CreatedAt = time.Now()
 	p.UpdatedAt = time.Now()
 
-	_, err := r.collection.InsertOne(ctx, p)
-	return err
+	if _, err := r.collection.InsertOne(ctx, p); err != nil {
+		return err
+	}
+	indexSearchTerms(ctx, r.collection, pekerjaanSearchFields, p.ID)
+	return nil
 }
 
-func (r *pekerjaanMongo) Update(id string, p *models.Pekerjaan) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) Update(ctx context.Context, id string, p *models.Pekerjaan) error {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.Update")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -113,15 +123,20 @@ func (r *pekerjaanMongo) Update(id string, p *models.Pekerjaan) error {
 			"gaji_range":      p.Gaji_range,
 			"updated_at":      p.UpdatedAt,
 		},
+		// term pencarian dihitung ulang di bawah atau oleh indexer
+		"$unset": bson.M{searchVersionField: ""},
 	}
 
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
-	return err
+	if _, err = r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update); err != nil {
+		return err
+	}
+	indexSearchTerms(ctx, r.collection, pekerjaanSearchFields, objID)
+	return nil
 }
 
-func (r *pekerjaanMongo) Delete(id string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) Delete(ctx context.Context, id string) error {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.Delete")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -132,87 +147,21 @@ func (r *pekerjaanMongo) Delete(id string) error {
 	return err
 }
 
-func (r *pekerjaanMongo) GetPekerjaan(search, sortBy, order string, page, limit int) ([]models.Pekerjaan, int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) GetPekerjaan(ctx context.Context, q query.Query) ([]models.Pekerjaan, query.Page, error) {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.GetPekerjaan")
+	defer end()
 
 	var pekerjaan []models.Pekerjaan
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
-	}
-
-	skip := int64((page - 1) * limit)
-
-	allowedSort := map[string]bool{
-		"_id": true, "alumni_id": true, "nama_perusahaan": true,
-		"posisi_jabatan": true, "bidang_industri": true,
-		"lokasi_kerja": true, "gaji_range": true,
-	}
-	if !allowedSort[sortBy] {
-		sortBy = "_id"
-	}
-	if order != "asc" && order != "desc" {
-		order = "asc"
-	}
-
-	// Build filter
-	filter := bson.M{"is_deleted": nil}
-	if search != "" {
-		filter = bson.M{
-			"$and": []bson.M{
-				{"is_deleted": nil},
-				{
-					"$or": []bson.M{
-						{"alumni_id": bson.M{"$regex": search, "$options": "i"}},
-						{"nama_perusahaan": bson.M{"$regex": search, "$options": "i"}},
-						{"posisi_jabatan": bson.M{"$regex": search, "$options": "i"}},
-						{"bidang_industri": bson.M{"$regex": search, "$options": "i"}},
-						{"lokasi_kerja": bson.M{"$regex": search, "$options": "i"}},
-						{"gaji_range": bson.M{"$regex": search, "$options": "i"}},
-					},
-				},
-			},
-		}
-	}
-
-	// Count total
-	total, err := r.collection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// Sort order
-	sortOrder := int32(1)
-	if order == "desc" {
-		sortOrder = -1
-	}
-
-	// Query with pagination and sorting
-	opts := options.Find().
-		SetSkip(skip).
-		SetLimit(int64(limit)).
-		SetSort(bson.M{sortBy: sortOrder})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	page, err := findPage(ctx, r.collection, r.timeouts.listMaxTime(), q, bson.M{"is_deleted": nil}, &pekerjaan)
 	if err != nil {
-		return nil, 0, err
-	}
-	defer cursor.Close(ctx)
-
-	if err = cursor.All(ctx, &pekerjaan); err != nil {
-		return nil, 0, err
+		return nil, query.Page{}, err
 	}
-
-	return pekerjaan, int(total), nil
+	return pekerjaan, page, nil
 }
 
-func (r *pekerjaanMongo) SoftDeleteByAdmin(alumniID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) SoftDeleteByAdmin(ctx context.Context, alumniID string) error {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.SoftDeleteByAdmin")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(alumniID)
 	if err != nil {
@@ -229,9 +178,9 @@ func (r *pekerjaanMongo) SoftDeleteByAdmin(alumniID string) error {
 	return err
 }
 
-func (r *pekerjaanMongo) SoftDeleteByUser(pekerjaanID, alumniID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) SoftDeleteByUser(ctx context.Context, pekerjaanID, alumniID string) error {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.SoftDeleteByUser")
+	defer end()
 
 	pekerjaanObjID, err := primitive.ObjectIDFromHex(pekerjaanID)
 	if err != nil {
@@ -253,83 +202,59 @@ func (r *pekerjaanMongo) SoftDeleteByUser(pekerjaanID, alumniID string) error {
 	return err
 }
 
-func (r *pekerjaanMongo) GetTrash(search, sortBy, order string, page, limit int) ([]models.Pekerjaan, int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) GetTrash(ctx context.Context, q query.Query) ([]models.Pekerjaan, query.Page, error) {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.GetTrash")
+	defer end()
 
 	var pekerjaan []models.Pekerjaan
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
+	page, err := findPage(ctx, r.collection, r.timeouts.listMaxTime(), q, bson.M{"is_deleted": bson.M{"$ne": nil}}, &pekerjaan)
+	if err != nil {
+		return nil, query.Page{}, err
 	}
+	return pekerjaan, page, nil
+}
 
-	skip := int64((page - 1) * limit)
+// SoftDelete memindahkan satu pekerjaan ke sampah. alumniID kosong berarti
+// tanpa cek kepemilikan (admin).
+func (r *pekerjaanMongo) SoftDelete(ctx context.Context, pekerjaanID, alumniID string) error {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.SoftDelete")
+	defer end()
 
-	allowedSort := map[string]bool{
-		"_id": true, "alumni_id": true, "nama_perusahaan": true,
-		"posisi_jabatan": true, "is_deleted": true,
-	}
-	if !allowedSort[sortBy] {
-		sortBy = "is_deleted"
-	}
-	if order != "asc" && order != "desc" {
-		order = "desc"
+	pekerjaanObjID, err := primitive.ObjectIDFromHex(pekerjaanID)
+	if err != nil {
+		return err
 	}
 
-	// Build filter for deleted items
-	filter := bson.M{"is_deleted": bson.M{"$ne": nil}}
-	if search != "" {
-		filter = bson.M{
-			"$and": []bson.M{
-				{"is_deleted": bson.M{"$ne": nil}},
-				{
-					"$or": []bson.M{
-						{"alumni_id": bson.M{"$regex": search, "$options": "i"}},
-						{"nama_perusahaan": bson.M{"$regex": search, "$options": "i"}},
-						{"posisi_jabatan": bson.M{"$regex": search, "$options": "i"}},
-					},
-				},
-			},
+	filter := bson.M{"_id": pekerjaanObjID, "is_deleted": nil}
+	if alumniID != "" {
+		alumniObjID, err := primitive.ObjectIDFromHex(alumniID)
+		if err != nil {
+			return err
 		}
+		filter["alumni_id"] = alumniObjID
 	}
 
-	// Count total
-	total, err := r.collection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// Sort order
-	sortOrder := int32(1)
-	if order == "desc" {
-		sortOrder = -1
+	update := bson.M{
+		"$set": bson.M{
+			"is_deleted": time.Now(),
+		},
 	}
 
-	// Query with pagination and sorting
-	opts := options.Find().
-		SetSkip(skip).
-		SetLimit(int64(limit)).
-		SetSort(bson.M{sortBy: sortOrder})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return nil, 0, err
+		return err
 	}
-	defer cursor.Close(ctx)
 
-	if err = cursor.All(ctx, &pekerjaan); err != nil {
-		return nil, 0, err
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
 	}
 
-	return pekerjaan, int(total), nil
+	return nil
 }
 
-func (r *pekerjaanMongo) Restore(pekerjaanID, alumniID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) Restore(ctx context.Context, pekerjaanID, alumniID string) error {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.Restore")
+	defer end()
 
 	pekerjaanObjID, err := primitive.ObjectIDFromHex(pekerjaanID)
 	if err != nil {
@@ -357,15 +282,15 @@ func (r *pekerjaanMongo) Restore(pekerjaanID, alumniID string) error {
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("data not found or already restored")
+		return ErrAlreadyRestored
 	}
 
 	return nil
 }
 
-func (r *pekerjaanMongo) RestoreByAdmin(alumniID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) RestoreByAdmin(ctx context.Context, alumniID string) error {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.RestoreByAdmin")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(alumniID)
 	if err != nil {
@@ -384,15 +309,15 @@ func (r *pekerjaanMongo) RestoreByAdmin(alumniID string) error {
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("no data found to restore")
+		return ErrNothingToRestore
 	}
 
 	return nil
 }
 
-func (r *pekerjaanMongo) HardDelete(pekerjaanID, alumniID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) HardDelete(ctx context.Context, pekerjaanID, alumniID string) error {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.HardDelete")
+	defer end()
 
 	pekerjaanObjID, err := primitive.ObjectIDFromHex(pekerjaanID)
 	if err != nil {
@@ -414,15 +339,15 @@ func (r *pekerjaanMongo) HardDelete(pekerjaanID, alumniID string) error {
 	}
 
 	if result.DeletedCount == 0 {
-		return fmt.Errorf("data not found or not in trash")
+		return ErrNotInTrash
 	}
 
 	return nil
 }
 
-func (r *pekerjaanMongo) HardDeleteByAdmin(alumniID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *pekerjaanMongo) HardDeleteByAdmin(ctx context.Context, alumniID string) error {
+	ctx, end := r.timeouts.start(ctx, "pekerjaan.HardDeleteByAdmin")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(alumniID)
 	if err != nil {
@@ -435,7 +360,7 @@ func (r *pekerjaanMongo) HardDeleteByAdmin(alumniID string) error {
 	}
 
 	if result.DeletedCount == 0 {
-		return fmt.Errorf("no data found in trash")
+		return ErrTrashEmpty
 	}
 
 	return nil
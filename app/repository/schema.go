@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"crud-app/app/query"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// alumniSchemaFields dipakai bersama oleh AlumniSchema dan AlumniPublicSchema.
+var alumniSchemaFields = []query.Field{
+	{Name: "_id", Type: query.ObjectID},
+	{Name: "nama", Type: query.String, Ops: []query.Op{query.Eq}},
+	{Name: "jurusan", Type: query.String, Ops: query.Equality},
+	{Name: "angkatan", Type: query.Int, Ops: query.Comparison},
+	{Name: "tahun_lulus", Type: query.Int, Ops: query.Comparison},
+	{Name: "created_at", Type: query.Time, Ops: query.Range},
+	{Name: "has_current_job", Type: query.Bool, Ops: []query.Op{query.Eq}, Stages: hasCurrentJobStages},
+}
+
+var alumniAliases = map[string]query.Alias{
+	"jurusan":         {Field: "jurusan", Op: query.In},
+	"angkatan":        {Field: "angkatan", Op: query.Eq},
+	"angkatan_min":    {Field: "angkatan", Op: query.Gte},
+	"angkatan_max":    {Field: "angkatan", Op: query.Lte},
+	"tahun_lulus":     {Field: "tahun_lulus", Op: query.Eq},
+	"tahun_lulus_min": {Field: "tahun_lulus", Op: query.Gte},
+	"tahun_lulus_max": {Field: "tahun_lulus", Op: query.Lte},
+	"created_at_from": {Field: "created_at", Op: query.Gte},
+	"created_at_to":   {Field: "created_at", Op: query.Lte},
+	"has_current_job": {Field: "has_current_job", Op: query.Eq},
+}
+
+// AlumniSchema adalah field yang bisa dipakai admin di listing alumni.
+// Email disimpan terenkripsi, jadi hanya bisa dicari exact match lewat
+// blind index (nilai filter diganti repository), tidak bisa di-sort atau
+// di-search.
+var AlumniSchema = query.MustSchema(&query.Schema{
+	Fields: append([]query.Field{
+		{Name: "email", Path: emailIndexField, Type: query.String, Ops: query.Equality},
+	}, alumniSchemaFields...),
+	Aliases:      withAlias(alumniAliases, "email", query.Alias{Field: "email", Op: query.In}),
+	Sort:         []string{"_id", "nama", "angkatan", "jurusan"},
+	DefaultSort:  "_id",
+	DefaultOrder: "asc",
+	Search:       []string{"nama", "jurusan", "angkatan"},
+	DefaultLimit: 10,
+	MaxLimit:     100,
+})
+
+// AlumniPublicSchema adalah AlumniSchema untuk non-admin: tanpa filter
+// email, agar keberadaan email yang tidak dipublikasikan tidak bisa ditebak.
+var AlumniPublicSchema = query.MustSchema(&query.Schema{
+	Fields:       alumniSchemaFields,
+	Aliases:      alumniAliases,
+	Sort:         []string{"_id", "nama", "angkatan", "jurusan"},
+	DefaultSort:  "_id",
+	DefaultOrder: "asc",
+	Search:       []string{"nama", "jurusan", "angkatan"},
+	DefaultLimit: 10,
+	MaxLimit:     100,
+})
+
+// withAlias mengembalikan salinan aliases ditambah satu alias.
+func withAlias(aliases map[string]query.Alias, name string, a query.Alias) map[string]query.Alias {
+	out := make(map[string]query.Alias, len(aliases)+1)
+	for k, v := range aliases {
+		out[k] = v
+	}
+	out[name] = a
+	return out
+}
+
+// pekerjaanFields dipakai bersama oleh listing pekerjaan dan sampah.
+var pekerjaanFields = []query.Field{
+	{Name: "_id", Type: query.ObjectID},
+	{Name: "alumni_id", Type: query.ObjectID, Ops: query.Equality},
+	{Name: "nama_perusahaan", Type: query.String, Ops: query.Equality},
+	{Name: "posisi_jabatan", Type: query.String, Ops: query.Equality},
+	{Name: "bidang_industri", Type: query.String, Ops: query.Equality},
+	{Name: "lokasi_kerja", Type: query.String, Ops: query.Equality},
+	{Name: "gaji_range", Type: query.String, Ops: query.Equality},
+	{Name: "status_pekerjaan", Type: query.String, Ops: query.Equality},
+	{Name: "tanggal_mulai_kerja", Type: query.Time, Ops: query.Range},
+	{Name: "tanggal_selesai_kerja", Type: query.Time, Ops: query.Range, Cond: endDateCond},
+	{Name: "currently_employed", Type: query.Bool, Ops: []query.Op{query.Eq}, Cond: currentlyEmployedCond},
+	{Name: "is_deleted", Type: query.Time, Ops: query.Range},
+}
+
+var pekerjaanAliases = map[string]query.Alias{
+	"alumni_id":                  {Field: "alumni_id", Op: query.Eq},
+	"status_pekerjaan":           {Field: "status_pekerjaan", Op: query.In},
+	"bidang_industri":            {Field: "bidang_industri", Op: query.In},
+	"lokasi_kerja":               {Field: "lokasi_kerja", Op: query.In},
+	"tanggal_mulai_kerja_from":   {Field: "tanggal_mulai_kerja", Op: query.Gte},
+	"tanggal_mulai_kerja_to":     {Field: "tanggal_mulai_kerja", Op: query.Lte},
+	"tanggal_selesai_kerja_from": {Field: "tanggal_selesai_kerja", Op: query.Gte},
+	"tanggal_selesai_kerja_to":   {Field: "tanggal_selesai_kerja", Op: query.Lte},
+	"currently_employed":         {Field: "currently_employed", Op: query.Eq},
+}
+
+// PekerjaanSchema adalah field yang bisa dipakai di listing pekerjaan.
+var PekerjaanSchema = query.MustSchema(&query.Schema{
+	Fields:       pekerjaanFields,
+	Aliases:      pekerjaanAliases,
+	Sort:         []string{"_id", "alumni_id", "nama_perusahaan", "posisi_jabatan", "bidang_industri", "lokasi_kerja", "gaji_range"},
+	DefaultSort:  "_id",
+	DefaultOrder: "asc",
+	Search:       []string{"nama_perusahaan", "posisi_jabatan", "bidang_industri", "lokasi_kerja", "gaji_range"},
+	DefaultLimit: 10,
+	MaxLimit:     100,
+})
+
+// TrashSchema adalah field yang bisa dipakai di listing sampah pekerjaan.
+var TrashSchema = query.MustSchema(&query.Schema{
+	Fields:       pekerjaanFields,
+	Aliases:      pekerjaanAliases,
+	Sort:         []string{"_id", "alumni_id", "nama_perusahaan", "posisi_jabatan", "is_deleted"},
+	DefaultSort:  "is_deleted",
+	DefaultOrder: "desc",
+	Search:       []string{"nama_perusahaan", "posisi_jabatan"},
+	DefaultLimit: 10,
+	MaxLimit:     50,
+})
+
+// UserSchema adalah field yang bisa dipakai di listing user.
+var UserSchema = query.MustSchema(&query.Schema{
+	Fields: []query.Field{
+		{Name: "_id", Type: query.ObjectID},
+		{Name: "username", Type: query.String, Ops: []query.Op{query.Eq}},
+		{Name: "email", Type: query.String, Ops: []query.Op{query.Eq}},
+		{Name: "role", Type: query.String, Ops: query.Equality},
+	},
+	Aliases: map[string]query.Alias{
+		"role": {Field: "role", Op: query.In},
+	},
+	Sort:         []string{"_id", "username", "email"},
+	DefaultSort:  "_id",
+	DefaultOrder: "asc",
+	Search:       []string{"username", "email"},
+	DefaultLimit: 10,
+	MaxLimit:     50,
+})
+
+// hasCurrentJobStages memfilter alumni yang punya (true) atau tidak punya
+// (false) pekerjaan yang sedang berjalan.
+func hasCurrentJobStages(_ query.Op, v interface{}) []bson.D {
+	return []bson.D{
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: pekerjaanCollection},
+			{Key: "let", Value: bson.D{{Key: "alumniId", Value: "$_id"}}},
+			{Key: "pipeline", Value: bson.A{
+				bson.D{{Key: "$match", Value: bson.D{
+					{Key: "$expr", Value: bson.D{{Key: "$and", Value: bson.A{
+						bson.D{{Key: "$eq", Value: bson.A{"$alumni_id", "$$alumniId"}}},
+						ongoingExpr("$$CURRENT"),
+					}}}},
+					{Key: "is_deleted", Value: nil},
+				}}},
+				bson.D{{Key: "$limit", Value: 1}},
+				bson.D{{Key: "$project", Value: bson.D{{Key: "_id", Value: 1}}}},
+			}},
+			{Key: "as", Value: "_current_jobs"},
+		}}},
+		{{Key: "$match", Value: bson.M{"_current_jobs.0": bson.M{"$exists": v.(bool)}}}},
+		{{Key: "$unset", Value: "_current_jobs"}},
+	}
+}
+
+// currentlyEmployedCond memfilter pekerjaan yang sedang berjalan (true)
+// atau yang belum mulai/sudah selesai (false).
+func currentlyEmployedCond(_ query.Op, v interface{}) bson.M {
+	ongoing := ongoingFilter(time.Now())
+	if v.(bool) {
+		return ongoing
+	}
+	return bson.M{"$nor": []bson.M{ongoing}}
+}
+
+// endDateCond tidak mengikutkan pekerjaan tanpa tanggal selesai (tanggal
+// kosong) pada batas atas.
+func endDateCond(op query.Op, v interface{}) bson.M {
+	c := query.Compare("tanggal_selesai_kerja", op, v)
+	if op == query.Lt || op == query.Lte {
+		return query.And(c, bson.M{"tanggal_selesai_kerja": bson.M{"$gt": noEndDate}})
+	}
+	return c
+}
+
+// AlumniFields adalah field alumni yang boleh diminta lewat ?fields=.
+// Kontak dan privacy tetap disaring per dokumen oleh kebijakan visibilitas
+// di service.
+var AlumniFields = query.Fieldset{
+	Default: []string{"id", "nim", "nama", "jurusan", "angkatan", "tahun_lulus", "email", "no_telepon", "alamat", "privacy", "created_at", "updated_at"},
+}
+
+// PekerjaanFields adalah field pekerjaan yang boleh dilihat per role.
+var PekerjaanFields = query.Fieldset{
+	Default: []string{"id", "alumni_id", "nama_perusahaan", "posisi_jabatan", "bidang_industri", "lokasi_kerja", "gaji_range",
+		"tanggal_mulai_kerja", "tanggal_selesai_kerja", "status_pekerjaan", "deskripsi_pekerjaan", "created_at", "updated_at", "is_deleted"},
+}
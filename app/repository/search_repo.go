@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"crud-app/app/fieldcrypt"
+	"crud-app/app/models"
+	"crud-app/app/search"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Jenis dokumen yang bisa dicari.
+const (
+	SearchAlumni    = "alumni"
+	SearchPekerjaan = "pekerjaan"
+)
+
+// SearchKinds adalah semua jenis dokumen yang bisa dicari.
+var SearchKinds = []string{SearchAlumni, SearchPekerjaan}
+
+// Field yang diindeks untuk full-text search, per koleksi.
+var (
+	alumniSearchFields    = []string{"nama", "nim", "jurusan"}
+	pekerjaanSearchFields = []string{"nama_perusahaan", "posisi_jabatan", "bidang_industri", "lokasi_kerja", "deskripsi_pekerjaan"}
+)
+
+const (
+	searchTermsField   = "search_terms"
+	searchVersionField = "search_version"
+	reindexBatchSize   = 500
+)
+
+// SearchRepository adalah backend full-text search. Backend lain (misalnya
+// index tertanam seperti Bleve) cukup memenuhi interface ini.
+type SearchRepository interface {
+	// Search mengembalikan hit untuk terms (hasil search.Terms) dari jenis
+	// kinds, terurut relevansi, dimulai dari hit ke-skip. total adalah
+	// jumlah seluruh hit.
+	Search(ctx context.Context, terms, kinds []string, skip, limit int) (hits []models.SearchHit, total int, err error)
+	search.Reindexer
+}
+
+// searchMongo memakai text index Mongo di field search_terms. Term sudah
+// di-stem oleh package search, jadi index memakai bahasa "none".
+type searchMongo struct {
+	alumni    *mongo.Collection
+	pekerjaan *mongo.Collection
+	timeouts  Timeouts
+	keys      *fieldcrypt.Keyring
+}
+
+func NewSearchRepository(db *mongo.Database, timeouts Timeouts, keys *fieldcrypt.Keyring) SearchRepository {
+	return &searchMongo{
+		alumni:    db.Collection("alumni"),
+		pekerjaan: db.Collection(pekerjaanCollection),
+		timeouts:  timeouts,
+		keys:      keys,
+	}
+}
+
+func (r *searchMongo) Search(ctx context.Context, terms, kinds []string, skip, limit int) ([]models.SearchHit, int, error) {
+	ctx, end := r.timeouts.start(ctx, "search.Search")
+	defer end()
+
+	text := bson.M{"$text": bson.M{"$search": strings.Join(terms, " "), "$language": "none"}}
+	var hits []models.SearchHit
+	total := 0
+	for _, kind := range kinds {
+		var h []models.SearchHit
+		var n int
+		var err error
+		switch kind {
+		case SearchAlumni:
+			h, n, err = r.searchCollection(ctx, r.alumni, kind, text, skip+limit, func(raw bson.Raw) (interface{}, error) {
+				var a models.Alumni
+				if err := bson.Unmarshal(raw, &a); err != nil {
+					return nil, err
+				}
+				return a, openAlumni(r.keys, &a)
+			})
+		case SearchPekerjaan:
+			filter := bson.M{"$and": []bson.M{text, {"is_deleted": nil}}}
+			h, n, err = r.searchCollection(ctx, r.pekerjaan, kind, filter, skip+limit, func(raw bson.Raw) (interface{}, error) {
+				var p models.Pekerjaan
+				return p, bson.Unmarshal(raw, &p)
+			})
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		hits = append(hits, h...)
+		total += n
+	}
+
+	// skor text index antar koleksi sebanding karena term-nya dihitung
+	// dengan analyzer yang sama
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if skip >= len(hits) {
+		return []models.SearchHit{}, total, nil
+	}
+	hits = hits[skip:]
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, total, nil
+}
+
+// searchCollection mengambil n hit teratas dari satu koleksi.
+func (r *searchMongo) searchCollection(ctx context.Context, coll *mongo.Collection, kind string, filter bson.M, n int, decode func(bson.Raw) (interface{}, error)) ([]models.SearchHit, int, error) {
+	maxTime := r.timeouts.listMaxTime()
+	total, err := coll.CountDocuments(ctx, filter, options.Count().SetMaxTime(maxTime))
+	if err != nil {
+		return nil, 0, listError(err)
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score, searchTermsField: 0, searchVersionField: 0}).
+		SetSort(bson.D{{Key: "score", Value: score}}).
+		SetLimit(int64(n)).
+		SetMaxTime(maxTime)
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, listError(err)
+	}
+	defer cursor.Close(ctx)
+
+	var raws []bson.Raw
+	if err := cursor.All(ctx, &raws); err != nil {
+		return nil, 0, listError(err)
+	}
+	hits := make([]models.SearchHit, 0, len(raws))
+	for _, raw := range raws {
+		data, err := decode(raw)
+		if err != nil {
+			return nil, 0, err
+		}
+		hits = append(hits, models.SearchHit{
+			Type:  kind,
+			ID:    raw.Lookup("_id").ObjectID(),
+			Score: raw.Lookup("score").Double(),
+			Data:  data,
+		})
+	}
+	return hits, int(total), nil
+}
+
+func (r *searchMongo) Reindex(ctx context.Context) (int, error) {
+	total := 0
+	for _, c := range []struct {
+		coll   *mongo.Collection
+		fields []string
+	}{
+		{r.alumni, alumniSearchFields},
+		{r.pekerjaan, pekerjaanSearchFields},
+	} {
+		n, err := r.reindexCollection(ctx, c.coll, c.fields)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// reindexCollection mengindeks dokumen yang search_version-nya bukan
+// search.Version, per batch.
+func (r *searchMongo) reindexCollection(ctx context.Context, coll *mongo.Collection, fields []string) (int, error) {
+	filter := bson.M{searchVersionField: bson.M{"$ne": search.Version}}
+	projection := bson.M{}
+	for _, f := range fields {
+		projection[f] = 1
+	}
+
+	done := 0
+	for {
+		n, err := r.reindexBatch(ctx, coll, filter, projection, fields)
+		done += n
+		if err != nil || n < reindexBatchSize {
+			return done, err
+		}
+	}
+}
+
+func (r *searchMongo) reindexBatch(ctx context.Context, coll *mongo.Collection, filter, projection bson.M, fields []string) (int, error) {
+	ctx, end := r.timeouts.start(ctx, "search.Reindex")
+	defer end()
+
+	cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(projection).SetLimit(reindexBatchSize))
+	if err != nil {
+		return 0, err
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return 0, err
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": doc["_id"]}).
+			SetUpdate(searchTermsUpdate(doc, fields)))
+	}
+	if _, err := coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+// searchTermsUpdate adalah update yang mengisi search_terms dari fields doc.
+func searchTermsUpdate(doc bson.M, fields []string) bson.M {
+	texts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if s, ok := doc[f].(string); ok {
+			texts = append(texts, s)
+		}
+	}
+	terms := search.Terms(texts...)
+	if terms == nil {
+		terms = []string{}
+	}
+	return bson.M{"$set": bson.M{searchTermsField: terms, searchVersionField: search.Version}}
+}
+
+// indexSearchTerms langsung mengindeks satu dokumen setelah ditulis.
+// Kegagalannya diabaikan: search_version dokumen belum terisi, jadi indexer
+// di background akan mengulanginya.
+func indexSearchTerms(ctx context.Context, coll *mongo.Collection, fields []string, id primitive.ObjectID) {
+	projection := bson.M{}
+	for _, f := range fields {
+		projection[f] = 1
+	}
+	var doc bson.M
+	if err := coll.FindOne(ctx, bson.M{"_id": id}, options.FindOne().SetProjection(projection)).Decode(&doc); err != nil {
+		return
+	}
+	coll.UpdateOne(ctx, bson.M{"_id": id}, searchTermsUpdate(doc, fields))
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"crud-app/app/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DefaultTimeout adalah batas waktu query jika tidak diatur per operasi.
+const DefaultTimeout = 10 * time.Second
+
+// DefaultListMaxTime adalah maxTimeMS query listing jika tidak diatur.
+const DefaultListMaxTime = 3 * time.Second
+
+// Timeouts mengatur batas waktu query Mongo. Kunci PerOperation memakai
+// format "<repo>.<Method>", contoh "alumni.GetAlumni" atau "user.GetByID".
+type Timeouts struct {
+	Default      time.Duration
+	PerOperation map[string]time.Duration
+	// ListMaxTime dikirim sebagai maxTimeMS pada query listing, supaya
+	// server Mongo menghentikan query yang terlalu berat.
+	ListMaxTime time.Duration
+}
+
+// DefaultTimeouts memakai DefaultTimeout untuk semua operasi.
+func DefaultTimeouts() Timeouts {
+	return Timeouts{Default: DefaultTimeout, ListMaxTime: DefaultListMaxTime}
+}
+
+func (t Timeouts) listMaxTime() time.Duration {
+	if t.ListMaxTime > 0 {
+		return t.ListMaxTime
+	}
+	return DefaultListMaxTime
+}
+
+// For mengembalikan batas waktu untuk operasi op.
+func (t Timeouts) For(op string) time.Duration {
+	if d, ok := t.PerOperation[op]; ok && d > 0 {
+		return d
+	}
+	if t.Default > 0 {
+		return t.Default
+	}
+	return DefaultTimeout
+}
+
+// start menyiapkan ctx untuk satu operasi repository: membuka span tracing
+// bernama op dan memberi batas waktu operasi tersebut. Jika request
+// dibatalkan (client putus), query ikut berhenti. Panggil end setelah selesai.
+func (t Timeouts) start(ctx context.Context, op string) (context.Context, func()) {
+	ctx, span := tracing.Start(ctx, op, attribute.String("db.operation.name", op))
+	ctx, cancel := context.WithTimeout(ctx, t.For(op))
+	return ctx, func() {
+		cancel()
+		span.End()
+	}
+}
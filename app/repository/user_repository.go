@@ -3,36 +3,37 @@ package repository
 import (
 	"context"
 	"crud-app/app/models"
-	"fmt"
+	"crud-app/app/query"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserRepository interface {
-	GetByUsername(username string) (*models.User, error)
-	GetByID(id string) (*models.User, error)
-	Create(user *models.User) error
-	GetUser(search, sortBy, order string, page, limit int) ([]models.User, int, error)
-	SoftDelete(id string) error
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	GetByID(ctx context.Context, id string) (*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	GetUser(ctx context.Context, q query.Query) ([]models.User, query.Page, error)
+	SoftDelete(ctx context.Context, id string) error
 }
 
 type userMongo struct {
 	collection *mongo.Collection
+	timeouts   Timeouts
 }
 
-func NewUserRepository(db *mongo.Database) UserRepository {
+func NewUserRepository(db *mongo.Database, timeouts Timeouts) UserRepository {
 	return &userMongo{
 		collection: db.Collection("users"),
+		timeouts:   timeouts,
 	}
 }
 
-func (r *userMongo) GetByUsername(username string) (*models.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *userMongo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	ctx, end := r.timeouts.start(ctx, "user.GetByUsername")
+	defer end()
 
 	var u models.User
 	err := r.collection.FindOne(ctx, bson.M{"username": username}).Decode(&u)
@@ -42,9 +43,9 @@ func (r *userMongo) GetByUsername(username string) (*models.User, error) {
 	return &u, nil
 }
 
-func (r *userMongo) GetByID(id string) (*models.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *userMongo) GetByID(ctx context.Context, id string) (*models.User, error) {
+	ctx, end := r.timeouts.start(ctx, "user.GetByID")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -59,9 +60,9 @@ func (r *userMongo) GetByID(id string) (*models.User, error) {
 	return &u, nil
 }
 
-func (r *userMongo) Create(u *models.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *userMongo) Create(ctx context.Context, u *models.User) error {
+	ctx, end := r.timeouts.start(ctx, "user.Create")
+	defer end()
 
 	u.ID = primitive.NewObjectID()
 
@@ -69,74 +70,21 @@ func (r *userMongo) Create(u *models.User) error {
 	return err
 }
 
-func (r *userMongo) GetUser(search, sortBy, order string, page, limit int) ([]models.User, int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *userMongo) GetUser(ctx context.Context, q query.Query) ([]models.User, query.Page, error) {
+	ctx, end := r.timeouts.start(ctx, "user.GetUser")
+	defer end()
 
 	var users []models.User
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
-	}
-
-	skip := int64((page - 1) * limit)
-
-	allowedSort := map[string]bool{"_id": true, "username": true, "email": true}
-	if !allowedSort[sortBy] {
-		sortBy = "_id"
-	}
-	if order != "asc" && order != "desc" {
-		order = "asc"
-	}
-
-	// Build filter
-	filter := bson.M{}
-	if search != "" {
-		filter = bson.M{
-			"$or": []bson.M{
-				{"username": bson.M{"$regex": search, "$options": "i"}},
-				{"email": bson.M{"$regex": search, "$options": "i"}},
-			},
-		}
-	}
-
-	// Count total
-	total, err := r.collection.CountDocuments(ctx, filter)
+	page, err := findPage(ctx, r.collection, r.timeouts.listMaxTime(), q, nil, &users)
 	if err != nil {
-		return nil, 0, err
+		return nil, query.Page{}, err
 	}
-
-	// Sort order
-	sortOrder := int32(1)
-	if order == "desc" {
-		sortOrder = -1
-	}
-
-	// Query with pagination and sorting
-	opts := options.Find().
-		SetSkip(skip).
-		SetLimit(int64(limit)).
-		SetSort(bson.M{sortBy: sortOrder})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer cursor.Close(ctx)
-
-	if err = cursor.All(ctx, &users); err != nil {
-		return nil, 0, err
-	}
-
-	return users, int(total), nil
+	return users, page, nil
 }
 
-func (r *userMongo) SoftDelete(id string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (r *userMongo) SoftDelete(ctx context.Context, id string) error {
+	ctx, end := r.timeouts.start(ctx, "user.SoftDelete")
+	defer end()
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -155,7 +103,7 @@ func (r *userMongo) SoftDelete(id string) error {
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
 	}
 
 	return nil
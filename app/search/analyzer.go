@@ -0,0 +1,88 @@
+// Package search berisi analyzer teks untuk full-text search: normalisasi
+// diakritik, tokenisasi, stemming bahasa Indonesia dan highlight. Term yang
+// dihasilkan disimpan di dokumen (search_terms) dan diindeks backend.
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Version dinaikkan setiap kali aturan analyzer berubah, supaya indexer
+// menghitung ulang search_terms dokumen lama.
+const Version = 1
+
+// minTermLength adalah panjang minimal term yang diindeks.
+const minTermLength = 2
+
+// stopwords tidak diindeks dan tidak dicari.
+var stopwords = map[string]bool{
+	"dan": true, "di": true, "ke": true, "dari": true, "yang": true,
+	"untuk": true, "pada": true, "dengan": true, "atau": true, "ini": true,
+	"itu": true, "the": true, "of": true, "and": true, "in": true, "at": true,
+}
+
+// Fold mengubah s menjadi huruf kecil tanpa diakritik ("Café" -> "cafe").
+func Fold(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// Token adalah satu kata di teks asli; Start dan End adalah posisi byte.
+type Token struct {
+	Text       string
+	Start, End int
+}
+
+// Tokenize memecah s menjadi kata (huruf dan angka).
+func Tokenize(s string) []Token {
+	var tokens []Token
+	start := -1
+	for i, r := range s {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			tokens = append(tokens, Token{Text: s[start:i], Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, Token{Text: s[start:], Start: start, End: len(s)})
+	}
+	return tokens
+}
+
+// term mengembalikan term untuk satu kata, atau "" jika kata diabaikan.
+func term(word string) string {
+	w := Fold(word)
+	if utf8.RuneCountInString(w) < minTermLength || stopwords[w] {
+		return ""
+	}
+	return Stem(w)
+}
+
+// Terms mengembalikan term unik dari semua teks, sesuai urutan kemunculan.
+func Terms(texts ...string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, text := range texts {
+		for _, tok := range Tokenize(text) {
+			if t := term(tok.Text); t != "" && !seen[t] {
+				seen[t] = true
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
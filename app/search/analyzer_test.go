@@ -0,0 +1,63 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Café", "cafe"},
+		{"NAÏVE Résumé", "naive resume"},
+		{"Ångström", "angstrom"},
+		{"Muñoz", "munoz"},
+		// bentuk terurai (e + combining acute) sama dengan bentuk tersusun
+		{"Cafe\u0301", "cafe"},
+		{"Teknik Informatika", "teknik informatika"},
+		{"S1 TI-2020", "s1 ti-2020"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Budi, S.Kom (2020) — café")
+	want := []Token{
+		{"Budi", 0, 4},
+		{"S", 6, 7},
+		{"Kom", 8, 11},
+		{"2020", 13, 17},
+		{"café", 23, 28},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %+v, want %+v", got, want)
+	}
+	if got := Tokenize(" ,.- "); got != nil {
+		t.Errorf("Tokenize(punctuation) = %+v, want nil", got)
+	}
+}
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		texts []string
+		want  []string
+	}{
+		{[]string{"Pendidikan Teknik Informatika"}, []string{"didik", "teknik", "informatika"}},
+		// stopword, kata satu huruf dan duplikat dibuang
+		{[]string{"Alumni dan alumni di Jakarta", "a JAKARTA"}, []string{"alumn", "jakarta"}},
+		// diakritik dilipat sebelum stemming
+		{[]string{"Pengusaha Café"}, []string{"usaha", "cafe"}},
+		{[]string{"dan yang di"}, nil},
+	}
+	for _, tt := range tests {
+		if got := Terms(tt.texts...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Terms(%q) = %q, want %q", tt.texts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+// Tag pembungkus kata yang cocok di highlight.
+const (
+	HighlightOpen  = "<em>"
+	HighlightClose = "</em>"
+)
+
+// snippetRadius adalah jumlah byte konteks di kiri dan kanan kata pertama
+// yang cocok saat teks dipotong.
+const snippetRadius = 80
+
+// Highlight menandai kata di text yang term-nya ada di terms. Teks lain
+// di-escape HTML sehingga aman ditampilkan. Teks panjang dipotong di sekitar
+// kecocokan pertama. ok false berarti tidak ada kata yang cocok.
+func Highlight(text string, terms map[string]bool) (out string, ok bool) {
+	tokens := Tokenize(text)
+	var matched []Token
+	for _, tok := range tokens {
+		if t := term(tok.Text); t != "" && terms[t] {
+			matched = append(matched, tok)
+		}
+	}
+	if len(matched) == 0 {
+		return "", false
+	}
+
+	from, to := 0, len(text)
+	if to > 2*snippetRadius {
+		from = snapStart(tokens, matched[0].Start-snippetRadius)
+		to = snapEnd(tokens, matched[0].End+snippetRadius, len(text))
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, m := range matched {
+		if m.Start < from || m.End > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:m.Start]))
+		b.WriteString(HighlightOpen)
+		b.WriteString(html.EscapeString(text[m.Start:m.End]))
+		b.WriteString(HighlightClose)
+		pos = m.End
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+// snapStart memajukan pos ke awal kata agar potongan tidak memutus kata.
+func snapStart(tokens []Token, pos int) int {
+	if pos <= 0 {
+		return 0
+	}
+	for _, t := range tokens {
+		if t.Start >= pos {
+			return t.Start
+		}
+	}
+	return pos
+}
+
+// snapEnd memundurkan pos ke akhir kata agar potongan tidak memutus kata.
+func snapEnd(tokens []Token, pos, max int) int {
+	if pos >= max {
+		return max
+	}
+	end := 0
+	for _, t := range tokens {
+		if t.End > pos {
+			break
+		}
+		end = t.End
+	}
+	return end
+}
+
+// TermSet mengubah term query menjadi set untuk Highlight.
+func TermSet(terms []string) map[string]bool {
+	set := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		set[t] = true
+	}
+	return set
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+		ok    bool
+	}{
+		{
+			name: "stemmed match", text: "Lulusan Pendidikan Informatika", query: "didik",
+			want: "Lulusan <em>Pendidikan</em> Informatika", ok: true,
+		},
+		{
+			name: "folded match keeps original text", text: "Bekerja di Café Rumah", query: "cafe",
+			want: "Bekerja di <em>Café</em> Rumah", ok: true,
+		},
+		{
+			name: "several matches", text: "Guru, guru dan GURU", query: "guru",
+			want: "<em>Guru</em>, <em>guru</em> dan <em>GURU</em>", ok: true,
+		},
+		{name: "no match", text: "Teknik Sipil", query: "informatika", ok: false},
+		{
+			name: "script tag is escaped", text: "<script>alert(1)</script> Budi", query: "budi",
+			want: "&lt;script&gt;alert(1)&lt;/script&gt; <em>Budi</em>", ok: true,
+		},
+		{
+			name: "matched word inside markup is escaped", text: `<img src=x onerror="alert('budi')">`, query: "budi",
+			want: `&lt;img src=x onerror=&#34;alert(&#39;<em>budi</em>&#39;)&#34;&gt;`, ok: true,
+		},
+		{
+			name: "highlight tags in input are escaped", text: "<em>Budi</em> & Ani", query: "ani",
+			want: "&lt;em&gt;Budi&lt;/em&gt; &amp; <em>Ani</em>", ok: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Highlight(tt.text, TermSet(Terms(tt.query)))
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Highlight(%q) = %q, %v; want %q, %v", tt.text, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	before := strings.Repeat("kata ", 40)
+	after := strings.Repeat(" lain", 40)
+	text := before + "<b>Budi</b>" + after
+
+	got, ok := Highlight(text, TermSet([]string{"budi"}))
+	if !ok {
+		t.Fatal("no match")
+	}
+	if !strings.HasPrefix(got, "…kata ") || !strings.HasSuffix(got, "lain…") {
+		t.Errorf("snippet not trimmed around the match: %q", got)
+	}
+	if !strings.Contains(got, "&lt;b&gt;<em>Budi</em>&lt;/b&gt;") {
+		t.Errorf("snippet lost or did not escape the match: %q", got)
+	}
+	if strings.Contains(got, "<b>") {
+		t.Errorf("snippet contains raw markup: %q", got)
+	}
+	// potongan tidak memutus kata
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(got, "…"), "…")
+	if strings.HasPrefix(trimmed, "ata") || strings.HasSuffix(trimmed, "lai") {
+		t.Errorf("snippet cuts a word: %q", got)
+	}
+	if len(trimmed) > 2*snippetRadius+len("&lt;b&gt;<em>Budi</em>&lt;/b&gt;") {
+		t.Errorf("snippet too long (%d bytes): %q", len(trimmed), got)
+	}
+}
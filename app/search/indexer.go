@@ -0,0 +1,36 @@
+package search
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Reindexer memperbarui search_terms dokumen yang belum diindeks atau
+// diindeks dengan Version lama, dan mengembalikan jumlah dokumen yang
+// diperbarui.
+type Reindexer interface {
+	Reindex(ctx context.Context) (int, error)
+}
+
+// RunIndexer adalah loop worker yang menjalankan Reindex setiap interval,
+// sehingga dokumen lama, dokumen yang ditulis di luar API, dan perubahan
+// Version ikut terindeks. Berhenti ketika ctx dibatalkan.
+func RunIndexer(ctx context.Context, r Reindexer, interval time.Duration) error {
+	for {
+		n, err := r.Reindex(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			// biasanya Mongo belum tersambung; monitor yang melaporkan
+			slog.Debug("search reindex failed", "error", err)
+		case n > 0:
+			slog.Info("search reindex", "documents", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package search
+
+import "strings"
+
+// Stem adalah stemmer bahasa Indonesia tanpa kamus (algoritma Tala, sama
+// dengan IndonesianStemmer milik Lucene). Hasilnya tidak selalu kata dasar
+// yang benar, tapi konsisten: bentuk berimbuhan dan kata dasarnya umumnya
+// menghasilkan stem yang sama. w harus sudah di-Fold.
+func Stem(w string) string {
+	s := stemmer{word: w, syllables: countVowels(w)}
+	if s.syllables > 2 {
+		s.removeParticle()
+	}
+	if s.syllables > 2 {
+		s.removePossessive()
+	}
+	if s.syllables > 2 {
+		before := s.word
+		s.removeFirstOrderPrefix()
+		if s.word != before {
+			if s.syllables > 2 {
+				s.removeSuffix()
+			}
+			if s.syllables > 2 {
+				s.removeSecondOrderPrefix()
+			}
+		} else {
+			if s.syllables > 2 {
+				s.removeSecondOrderPrefix()
+			}
+			if s.syllables > 2 {
+				s.removeSuffix()
+			}
+		}
+	}
+	return s.word
+}
+
+// Imbuhan yang sudah dilepas, menentukan akhiran mana yang boleh dilepas.
+const (
+	removedKe = 1 << iota
+	removedPeng
+	removedDi
+	removedMeng
+	removedTer
+	removedBer
+	removedPe
+)
+
+type stemmer struct {
+	word      string
+	syllables int
+	flags     int
+}
+
+func isVowel(b byte) bool {
+	switch b {
+	case 'a', 'e', 'i', 'o', 'u':
+		return true
+	}
+	return false
+}
+
+func countVowels(w string) int {
+	n := 0
+	for i := 0; i < len(w); i++ {
+		if isVowel(w[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *stemmer) trimSuffix(suffix string) bool {
+	if !strings.HasSuffix(s.word, suffix) {
+		return false
+	}
+	s.word = strings.TrimSuffix(s.word, suffix)
+	s.syllables--
+	return true
+}
+
+// trimPrefix melepas n byte awal lalu menambahkan replace di depan.
+func (s *stemmer) trimPrefix(n int, replace string, flag int) {
+	s.word = replace + s.word[n:]
+	s.syllables--
+	s.flags |= flag
+}
+
+func (s *stemmer) removeParticle() {
+	_ = s.trimSuffix("kah") || s.trimSuffix("lah") || s.trimSuffix("pun")
+}
+
+func (s *stemmer) removePossessive() {
+	_ = s.trimSuffix("ku") || s.trimSuffix("mu") || s.trimSuffix("nya")
+}
+
+// vowelAt melaporkan apakah byte ke-i adalah huruf vokal.
+func (s *stemmer) vowelAt(i int) bool {
+	return len(s.word) > i && isVowel(s.word[i])
+}
+
+func (s *stemmer) removeFirstOrderPrefix() {
+	w := s.word
+	switch {
+	case strings.HasPrefix(w, "meng"):
+		s.trimPrefix(4, "", removedMeng)
+	case strings.HasPrefix(w, "meny") && s.vowelAt(4):
+		s.trimPrefix(4, "s", removedMeng)
+	case strings.HasPrefix(w, "men"), strings.HasPrefix(w, "mem"):
+		s.trimPrefix(3, "", removedMeng)
+	case strings.HasPrefix(w, "me"):
+		s.trimPrefix(2, "", removedMeng)
+	case strings.HasPrefix(w, "peng"):
+		s.trimPrefix(4, "", removedPeng)
+	case strings.HasPrefix(w, "peny") && s.vowelAt(4):
+		s.trimPrefix(4, "s", removedPeng)
+	case strings.HasPrefix(w, "peny"):
+		s.trimPrefix(4, "", removedPeng)
+	case strings.HasPrefix(w, "pen") && s.vowelAt(3):
+		s.trimPrefix(3, "t", removedPeng)
+	case strings.HasPrefix(w, "pen"), strings.HasPrefix(w, "pem"):
+		s.trimPrefix(3, "", removedPeng)
+	case strings.HasPrefix(w, "di"):
+		s.trimPrefix(2, "", removedDi)
+	case strings.HasPrefix(w, "ter"):
+		s.trimPrefix(3, "", removedTer)
+	case strings.HasPrefix(w, "ke"):
+		s.trimPrefix(2, "", removedKe)
+	}
+}
+
+func (s *stemmer) removeSecondOrderPrefix() {
+	w := s.word
+	switch {
+	case strings.HasPrefix(w, "ber"):
+		s.trimPrefix(3, "", removedBer)
+	case w == "belajar":
+		s.trimPrefix(3, "", removedBer)
+	case strings.HasPrefix(w, "be") && len(w) > 4 && !isVowel(w[2]) && w[3] == 'e' && w[4] == 'r':
+		s.trimPrefix(2, "", removedBer)
+	case strings.HasPrefix(w, "per"):
+		s.trimPrefix(3, "", 0)
+	case w == "pelajar":
+		s.trimPrefix(3, "", 0)
+	case strings.HasPrefix(w, "pe"):
+		s.trimPrefix(2, "", removedPe)
+	}
+}
+
+func (s *stemmer) removeSuffix() {
+	switch {
+	case strings.HasSuffix(s.word, "kan") && s.flags&(removedKe|removedPeng|removedPe) == 0:
+		s.trimSuffix("kan")
+	case strings.HasSuffix(s.word, "an") && s.flags&(removedDi|removedMeng|removedTer) == 0:
+		s.trimSuffix("an")
+	case strings.HasSuffix(s.word, "i") && !strings.HasSuffix(s.word, "si") && s.flags&(removedBer|removedKe|removedPeng) == 0:
+		s.trimSuffix("i")
+	}
+}
@@ -0,0 +1,82 @@
+package search
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word, want string
+	}{
+		// partikel dan kata ganti milik
+		{"bukukah", "buku"},
+		{"adalah", "ada"},
+		{"bukupun", "buku"},
+		{"bukuku", "buku"},
+		{"bukumu", "buku"},
+		{"bukunya", "buku"},
+		// awalan pertama
+		{"mengukur", "ukur"},
+		{"menyapu", "sapu"},
+		{"menduga", "duga"},
+		{"membaca", "baca"},
+		{"merusak", "rusak"},
+		{"pengukur", "ukur"},
+		{"penulis", "tulis"},
+		{"diculik", "culik"},
+		{"terlibat", "libat"},
+		// awalan kedua
+		{"bertahan", "tahan"},
+		{"bekerja", "kerja"},
+		{"belajar", "ajar"},
+		{"pelajar", "ajar"},
+		// akhiran
+		{"lulusan", "lulus"},
+		{"kelahiran", "lahir"},
+		{"kemenangan", "menang"},
+		{"kesaksian", "saksi"},
+		{"keadilan", "adil"},
+		// awalan dan akhiran bertumpuk
+		{"penyalahgunaan", "salahguna"},
+		{"menyalahgunakan", "salahguna"},
+		{"disalahgunakan", "salahguna"},
+		{"pertanggungjawaban", "tanggungjawab"},
+		{"mempertanggungjawabkan", "tanggungjawab"},
+		{"pelaksanaan", "laksana"},
+		{"melaksanakan", "laksana"},
+		{"pendidikan", "didik"},
+		{"pekerjaan", "kerja"},
+		{"meluluskan", "lulus"},
+		{"perdamaian", "damai"},
+		{"mendamaikan", "damai"},
+		{"pertahanan", "tahan"},
+		{"penyebaran", "sebar"},
+		{"menyebarkan", "sebar"},
+		// "-si" bukan akhiran "-i"
+		{"informasi", "informasi"},
+		// kata dengan dua suku kata atau kurang tidak diubah
+		{"buku", "buku"},
+		{"dia", "dia"},
+		{"sikap", "sikap"},
+		{"jalan", "jalan"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Stem(tt.word); got != tt.want {
+			t.Errorf("Stem(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestStemConflatesInflections(t *testing.T) {
+	groups := [][]string{
+		{"didik", "mendidik", "pendidik", "pendidikan"},
+		{"culik", "menculik", "diculik", "penculikan"},
+		{"saksi", "kesaksian", "menyaksikan"},
+	}
+	for _, g := range groups {
+		for _, w := range g[1:] {
+			if Stem(w) != Stem(g[0]) {
+				t.Errorf("Stem(%q) = %q, want same stem as %q (%q)", w, Stem(w), g[0], Stem(g[0]))
+			}
+		}
+	}
+}
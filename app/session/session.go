@@ -0,0 +1,106 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"time"
+)
+
+// Nama cookie dan header untuk login mode cookie. Cookie sesi berisi JWT
+// (HttpOnly), cookie CSRF bisa dibaca JavaScript agar frontend dapat
+// mengirimkannya kembali lewat header CSRFHeader (double-submit).
+const (
+	CookieName     = "session"
+	CSRFCookieName = "csrf_token"
+	CSRFHeader     = "X-CSRF-Token"
+
+	// CSRFClaim adalah claim JWT yang mengikat token CSRF ke sesi, sehingga
+	// cookie CSRF yang disisipkan subdomain lain tidak bisa dipakai.
+	CSRFClaim = "csrf"
+)
+
+// Options adalah atribut cookie yang dipasang.
+type Options struct {
+	Secure   bool
+	SameSite http.SameSite
+	Domain   string
+}
+
+// ParseSameSite mengubah "lax", "strict" atau "none" menjadi http.SameSite.
+func ParseSameSite(s string) http.SameSite {
+	switch s {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// NewCSRFToken membuat token acak 32 byte.
+func NewCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Set memasang cookie sesi dan cookie CSRF dengan umur ttl.
+func Set(w http.ResponseWriter, opts Options, token, csrf string, ttl time.Duration) {
+	expires := time.Now().Add(ttl)
+	http.SetCookie(w, &http.Cookie{
+		Name: CookieName, Value: token, Path: "/", Domain: opts.Domain,
+		Expires: expires, MaxAge: int(ttl.Seconds()),
+		HttpOnly: true, Secure: opts.Secure, SameSite: opts.SameSite,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name: CSRFCookieName, Value: csrf, Path: "/", Domain: opts.Domain,
+		Expires: expires, MaxAge: int(ttl.Seconds()),
+		Secure: opts.Secure, SameSite: opts.SameSite,
+	})
+}
+
+// Clear menghapus kedua cookie.
+func Clear(w http.ResponseWriter, opts Options) {
+	for _, name := range []string{CookieName, CSRFCookieName} {
+		http.SetCookie(w, &http.Cookie{
+			Name: name, Value: "", Path: "/", Domain: opts.Domain,
+			Expires: time.Unix(0, 0), MaxAge: -1,
+			HttpOnly: name == CookieName, Secure: opts.Secure, SameSite: opts.SameSite,
+		})
+	}
+}
+
+// Token mengembalikan JWT dari cookie sesi, atau "" jika tidak ada.
+func Token(r *http.Request) string {
+	c, err := r.Cookie(CookieName)
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
+
+// ValidCSRF memeriksa header CSRF sama dengan cookie CSRF dan dengan
+// claim yang tersimpan di sesi.
+func ValidCSRF(r *http.Request, claim string) bool {
+	header := r.Header.Get(CSRFHeader)
+	c, err := r.Cookie(CSRFCookieName)
+	if header == "" || claim == "" || err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(header), []byte(c.Value)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(header), []byte(claim)) == 1
+}
+
+// Safe melaporkan apakah method tidak mengubah state (tidak perlu CSRF).
+func Safe(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidCSRF(t *testing.T) {
+	const token = "csrf-token-1"
+	tests := []struct {
+		name   string
+		header string
+		cookie string
+		claim  string
+		want   bool
+	}{
+		{"all match", token, token, token, true},
+		{"missing header", "", token, token, false},
+		{"missing cookie", token, "", token, false},
+		{"missing claim", token, token, "", false},
+		{"all empty", "", "", "", false},
+		{"header differs from cookie", "other", token, token, false},
+		{"header differs from claim", token, token, "other", false},
+		// cookie yang disisipkan subdomain lain: header dan cookie sama,
+		// tapi bukan token sesi ini
+		{"injected cookie", "attacker", "attacker", token, false},
+		{"prefix of token", token[:4], token, token, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/v1/alumni", nil)
+			if tt.header != "" {
+				r.Header.Set(CSRFHeader, tt.header)
+			}
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: tt.cookie})
+			}
+			if got := ValidCSRF(r, tt.claim); got != tt.want {
+				t.Errorf("ValidCSRF = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafe(t *testing.T) {
+	for method, want := range map[string]bool{
+		"GET": true, "HEAD": true, "OPTIONS": true,
+		"POST": false, "PUT": false, "PATCH": false, "DELETE": false,
+	} {
+		if got := Safe(method); got != want {
+			t.Errorf("Safe(%s) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestToken(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	if got := Token(r); got != "" {
+		t.Errorf("Token without cookie = %q, want empty", got)
+	}
+	r.AddCookie(&http.Cookie{Name: CookieName, Value: "jwt"})
+	if got := Token(r); got != "jwt" {
+		t.Errorf("Token = %q, want jwt", got)
+	}
+}
+
+func TestSetAndClearCookies(t *testing.T) {
+	opts := Options{Secure: true, SameSite: http.SameSiteStrictMode, Domain: "example.com"}
+	rec := httptest.NewRecorder()
+	Set(rec, opts, "jwt", "csrf", time.Hour)
+
+	cookies := map[string]*http.Cookie{}
+	for _, c := range rec.Result().Cookies() {
+		cookies[c.Name] = c
+	}
+	sess, csrf := cookies[CookieName], cookies[CSRFCookieName]
+	if sess == nil || csrf == nil {
+		t.Fatalf("cookies = %v, want %s and %s", rec.Result().Cookies(), CookieName, CSRFCookieName)
+	}
+	if sess.Value != "jwt" || !sess.HttpOnly || !sess.Secure || sess.SameSite != http.SameSiteStrictMode || sess.MaxAge != 3600 {
+		t.Errorf("session cookie = %+v", sess)
+	}
+	// frontend harus bisa membaca cookie CSRF
+	if csrf.Value != "csrf" || csrf.HttpOnly || !csrf.Secure {
+		t.Errorf("csrf cookie = %+v", csrf)
+	}
+
+	rec = httptest.NewRecorder()
+	Clear(rec, opts)
+	for _, c := range rec.Result().Cookies() {
+		if c.Value != "" || c.MaxAge >= 0 {
+			t.Errorf("cleared cookie %s = %+v, want expired", c.Name, c)
+		}
+	}
+}
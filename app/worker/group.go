@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Status adalah keadaan sebuah background worker.
+type Status string
+
+const (
+	StatusRunning Status = "running"
+	StatusStopped Status = "stopped"
+	StatusFailed  Status = "failed"
+)
+
+// State adalah snapshot keadaan worker, dipakai misalnya oleh readiness check.
+type State struct {
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Group menjalankan background worker yang berhenti bersama saat shutdown.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	states map[string]State
+}
+
+func NewGroup(parent context.Context) *Group {
+	ctx, cancel := context.WithCancel(parent)
+	return &Group{ctx: ctx, cancel: cancel, states: map[string]State{}}
+}
+
+// Go menjalankan fn di goroutine baru. fn harus berhenti ketika ctx selesai.
+func (g *Group) Go(name string, fn func(ctx context.Context) error) {
+	g.setState(name, State{Status: StatusRunning, StartedAt: time.Now()})
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		err := fn(g.ctx)
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		st := g.states[name]
+		if err != nil && !errors.Is(err, context.Canceled) {
+			st.Status, st.Error = StatusFailed, err.Error()
+		} else {
+			st.Status = StatusStopped
+		}
+		g.states[name] = st
+	}()
+}
+
+// States mengembalikan salinan keadaan semua worker.
+func (g *Group) States() map[string]State {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	out := make(map[string]State, len(g.states))
+	for name, st := range g.states {
+		out[name] = st
+	}
+	return out
+}
+
+// Stop membatalkan semua worker lalu menunggu sampai selesai atau ctx habis.
+func (g *Group) Stop(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.New("timed out waiting for background workers to stop")
+	}
+}
+
+func (g *Group) setState(name string, st State) {
+	g.mu.Lock()
+	g.states[name] = st
+	g.mu.Unlock()
+}
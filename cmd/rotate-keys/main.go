@@ -0,0 +1,95 @@
+// Command rotate-keys mengenkripsi ulang kontak alumni dengan key aktif
+// (encryption.active_key). Jalankan setelah menambah key baru dan
+// menjadikannya aktif. Dokumen plaintext lama ikut dienkripsi.
+//
+// Di akhir, dokumen yang belum memakai key aktif dihitung ulang. Jika masih
+// ada (misalnya terus diubah selama rotasi), perintah keluar dengan status 1
+// dan menyebut jumlahnya; jalankan ulang. Key lama baru boleh dihapus dari
+// konfigurasi setelah perintah ini mencatat bahwa semua dokumen sudah
+// memakai key aktif.
+//
+//	go run ./cmd/rotate-keys -profile prod [-all]
+//
+// Konfigurasi dibaca dengan cara yang sama seperti server (file, env, flag).
+package main
+
+import (
+	"context"
+	"crud-app/app/fieldcrypt"
+	"crud-app/app/repository"
+	"crud-app/config"
+	"crud-app/database"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		slog.Error("key rotation failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("rotate-keys", flag.ContinueOnError)
+	all := fs.Bool("all", false, "re-encrypt every document, including those already on the active key")
+	profile := fs.String("profile", "", "config profile: dev, test or prod (env APP_ENV)")
+	file := fs.String("config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+	mongoURI := fs.String("mongo-uri", "", "MongoDB connection URI (env MONGO_URI)")
+	mongoDB := fs.String("mongo-db", "", "MongoDB database name (env MONGO_DATABASE)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// flag selain -all diteruskan ke config.Load
+	values := map[string]string{"profile": *profile, "config": *file, "mongo-uri": *mongoURI, "mongo-db": *mongoDB}
+	var loadArgs []string
+	fs.Visit(func(f *flag.Flag) {
+		if v, ok := values[f.Name]; ok {
+			loadArgs = append(loadArgs, "-"+f.Name, v)
+		}
+	})
+	cfg, err := config.Load(loadArgs)
+	if err != nil {
+		return err
+	}
+	keys, err := fieldcrypt.ParseKeyring(cfg.Encryption.Keys, cfg.Encryption.ActiveKey, cfg.Encryption.BlindIndexKey)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client, err := database.ConnectDB(cfg.Mongo)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(context.Background())
+
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.Mongo.ConnectTimeout.Duration)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		return fmt.Errorf("connect to MongoDB: %w", err)
+	}
+
+	timeouts := repository.Timeouts{
+		Default:      cfg.Mongo.QueryTimeout.Duration,
+		PerOperation: cfg.Mongo.OperationTimeoutMap(),
+		ListMaxTime:  cfg.Mongo.ListMaxTime.Duration,
+	}
+	repo := repository.NewAlumniRepository(database.GetDatabase(client, cfg.Mongo.Database), timeouts, keys)
+
+	slog.Info("rotating alumni contact keys", "active_key", keys.ActiveKeyID(), "all", *all)
+	n, err := repo.RotateKeys(ctx, *all)
+	slog.Info("alumni re-encrypted", "documents", n)
+	if err != nil {
+		return err
+	}
+	slog.Info("all alumni documents use the active key, old keys can be removed", "active_key", keys.ActiveKeyID())
+	return nil
+}
@@ -0,0 +1,458 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"crud-app/app/fieldcrypt"
+	"crud-app/app/i18n"
+)
+
+// Profile yang dikenali. Setiap profile punya nilai default sendiri.
+const (
+	ProfileDev  = "dev"
+	ProfileTest = "test"
+	ProfileProd = "prod"
+)
+
+// Config adalah konfigurasi aplikasi. Urutan prioritas sumber nilai:
+// default profile < file (YAML/TOML) < environment variable < flag.
+type Config struct {
+	Profile    string           `yaml:"-" toml:"-" json:"profile"`
+	Server     ServerConfig     `yaml:"server" toml:"server" json:"server"`
+	Mongo      MongoConfig      `yaml:"mongo" toml:"mongo" json:"mongo"`
+	Auth       AuthConfig       `yaml:"auth" toml:"auth" json:"auth"`
+	I18n       I18nConfig       `yaml:"i18n" toml:"i18n" json:"i18n"`
+	Log        LogConfig        `yaml:"log" toml:"log" json:"log"`
+	Tracing    TracingConfig    `yaml:"tracing" toml:"tracing" json:"tracing"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit" toml:"rate_limit" json:"rate_limit"`
+	CORS       CORSConfig       `yaml:"cors" toml:"cors" json:"cors"`
+	Security   SecurityConfig   `yaml:"security" toml:"security" json:"security"`
+	API        APIConfig        `yaml:"api" toml:"api" json:"api"`
+	Search     SearchConfig     `yaml:"search" toml:"search" json:"search"`
+	Encryption EncryptionConfig `yaml:"encryption" toml:"encryption" json:"encryption"`
+}
+
+type ServerConfig struct {
+	Port              int      `yaml:"port" toml:"port" json:"port"`
+	ReadTimeout       Duration `yaml:"read_timeout" toml:"read_timeout" json:"read_timeout"`
+	ReadHeaderTimeout Duration `yaml:"read_header_timeout" toml:"read_header_timeout" json:"read_header_timeout"`
+	WriteTimeout      Duration `yaml:"write_timeout" toml:"write_timeout" json:"write_timeout"`
+	IdleTimeout       Duration `yaml:"idle_timeout" toml:"idle_timeout" json:"idle_timeout"`
+	ShutdownTimeout   Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" json:"shutdown_timeout"`
+}
+
+type MongoConfig struct {
+	URI               string              `yaml:"uri" toml:"uri" json:"uri"`
+	Database          string              `yaml:"database" toml:"database" json:"database"`
+	ConnectTimeout    Duration            `yaml:"connect_timeout" toml:"connect_timeout" json:"connect_timeout"`
+	QueryTimeout      Duration            `yaml:"query_timeout" toml:"query_timeout" json:"query_timeout"`
+	OperationTimeouts map[string]Duration `yaml:"operation_timeouts" toml:"operation_timeouts" json:"operation_timeouts,omitempty"`
+	ListMaxTime       Duration            `yaml:"list_max_time" toml:"list_max_time" json:"list_max_time"`
+	PingInterval      Duration            `yaml:"ping_interval" toml:"ping_interval" json:"ping_interval"`
+	RetryInterval     Duration            `yaml:"retry_interval" toml:"retry_interval" json:"retry_interval"`
+	MaxRetryInterval  Duration            `yaml:"max_retry_interval" toml:"max_retry_interval" json:"max_retry_interval"`
+}
+
+type AuthConfig struct {
+	JWTSecret string        `yaml:"jwt_secret" toml:"jwt_secret" json:"jwt_secret"`
+	TokenTTL  Duration      `yaml:"token_ttl" toml:"token_ttl" json:"token_ttl"`
+	Session   SessionConfig `yaml:"session" toml:"session" json:"session"`
+}
+
+// SessionConfig mengatur cookie sesi untuk login mode cookie
+// (POST /login?mode=cookie). SameSite: "lax", "strict" atau "none";
+// "none" wajib bersama cookie_secure.
+type SessionConfig struct {
+	CookieSecure bool   `yaml:"cookie_secure" toml:"cookie_secure" json:"cookie_secure"`
+	SameSite     string `yaml:"same_site" toml:"same_site" json:"same_site"`
+	Domain       string `yaml:"domain" toml:"domain" json:"domain,omitempty"`
+}
+
+type I18nConfig struct {
+	DefaultLanguage string `yaml:"default_language" toml:"default_language" json:"default_language"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level" toml:"level" json:"level"`
+	Format string `yaml:"format" toml:"format" json:"format"`
+}
+
+// TracingConfig mengatur OpenTelemetry. Exporter: "none", "stdout" atau "otlp".
+type TracingConfig struct {
+	ServiceName  string  `yaml:"service_name" toml:"service_name" json:"service_name"`
+	Exporter     string  `yaml:"exporter" toml:"exporter" json:"exporter"`
+	OTLPEndpoint string  `yaml:"otlp_endpoint" toml:"otlp_endpoint" json:"otlp_endpoint"`
+	OTLPInsecure bool    `yaml:"otlp_insecure" toml:"otlp_insecure" json:"otlp_insecure"`
+	SampleRatio  float64 `yaml:"sample_ratio" toml:"sample_ratio" json:"sample_ratio"`
+}
+
+// RateLimitConfig mengatur rate limit. Backend: "memory" (satu instance)
+// atau "mongo" (dibagi antar instance). Nama policy yang dipakai router:
+// "default" (semua request), "auth" (/login, /register) dan "list"
+// (endpoint listing); policy yang tidak ada berarti tanpa batas.
+type RateLimitConfig struct {
+	Enabled           bool                       `yaml:"enabled" toml:"enabled" json:"enabled"`
+	Backend           string                     `yaml:"backend" toml:"backend" json:"backend"`
+	TrustForwardedFor bool                       `yaml:"trust_forwarded_for" toml:"trust_forwarded_for" json:"trust_forwarded_for"`
+	Policies          map[string]RateLimitPolicy `yaml:"policies" toml:"policies" json:"policies"`
+}
+
+// RateLimitPolicy: Requests per Window dengan Burst opsional (default
+// sama dengan Requests). Key: "ip", "user" atau "api_key".
+type RateLimitPolicy struct {
+	Requests int      `yaml:"requests" toml:"requests" json:"requests"`
+	Window   Duration `yaml:"window" toml:"window" json:"window"`
+	Burst    int      `yaml:"burst" toml:"burst" json:"burst,omitempty"`
+	Key      string   `yaml:"key" toml:"key" json:"key"`
+}
+
+// CORSConfig mengatur akses lintas origin dari frontend. AllowedOrigins
+// boleh berisi "*" atau pola subdomain "https://*.example.com"; "*" tidak
+// boleh dipakai bersama AllowCredentials.
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins" toml:"allowed_origins" json:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods" toml:"allowed_methods" json:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers" toml:"allowed_headers" json:"allowed_headers"`
+	ExposedHeaders   []string `yaml:"exposed_headers" toml:"exposed_headers" json:"exposed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials" toml:"allow_credentials" json:"allow_credentials"`
+	MaxAge           Duration `yaml:"max_age" toml:"max_age" json:"max_age"`
+}
+
+// SearchConfig mengatur full-text search. Backend saat ini hanya "mongo"
+// (text index); indexer mengisi term dokumen lama setiap reindex_interval.
+type SearchConfig struct {
+	Backend         string   `yaml:"backend" toml:"backend" json:"backend"`
+	ReindexInterval Duration `yaml:"reindex_interval" toml:"reindex_interval" json:"reindex_interval"`
+}
+
+// EncryptionConfig mengatur enkripsi kontak alumni at rest. Keys berisi
+// key encryption key (base64, 32 byte) per key id; active_key dipakai untuk
+// dokumen baru, key lain tetap dipakai membaca sampai rotate-keys selesai.
+// blind_index_key (base64, 32 byte) dipakai untuk pencarian email dan tidak
+// ikut dirotasi.
+type EncryptionConfig struct {
+	Keys          map[string]string `yaml:"keys" toml:"keys" json:"keys,omitempty"`
+	ActiveKey     string            `yaml:"active_key" toml:"active_key" json:"active_key"`
+	BlindIndexKey string            `yaml:"blind_index_key" toml:"blind_index_key" json:"blind_index_key"`
+}
+
+// SecurityConfig mengatur header keamanan browser. HSTS dikirim jika
+// hsts_max_age > 0; default hanya aktif di profile prod.
+type SecurityConfig struct {
+	ContentSecurityPolicy string   `yaml:"content_security_policy" toml:"content_security_policy" json:"content_security_policy"`
+	HSTSMaxAge            Duration `yaml:"hsts_max_age" toml:"hsts_max_age" json:"hsts_max_age"`
+	HSTSIncludeSubdomains bool     `yaml:"hsts_include_subdomains" toml:"hsts_include_subdomains" json:"hsts_include_subdomains"`
+}
+
+// APIConfig mengatur masa transisi alias route lama (tanpa /api/v1).
+// Tanggal ditulis dalam RFC 3339, misalnya "2027-04-30T00:00:00Z".
+type APIConfig struct {
+	LegacyDeprecatedAt time.Time `yaml:"legacy_deprecated_at" toml:"legacy_deprecated_at" json:"legacy_deprecated_at"`
+	LegacySunset       time.Time `yaml:"legacy_sunset" toml:"legacy_sunset" json:"legacy_sunset"`
+}
+
+// Duration adalah time.Duration yang dibaca/ditulis sebagai string ("10s").
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(strings.TrimSpace(string(b)))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+// Defaults mengembalikan nilai default untuk sebuah profile.
+func Defaults(profile string) (*Config, error) {
+	cfg := &Config{
+		Profile: profile,
+		Server: ServerConfig{
+			Port:              3000,
+			ReadTimeout:       Duration{15 * time.Second},
+			ReadHeaderTimeout: Duration{5 * time.Second},
+			WriteTimeout:      Duration{30 * time.Second},
+			IdleTimeout:       Duration{60 * time.Second},
+			ShutdownTimeout:   Duration{20 * time.Second},
+		},
+		Mongo: MongoConfig{
+			URI:              "mongodb://localhost:27017",
+			Database:         "alumni_db",
+			ConnectTimeout:   Duration{10 * time.Second},
+			QueryTimeout:     Duration{10 * time.Second},
+			ListMaxTime:      Duration{3 * time.Second},
+			PingInterval:     Duration{15 * time.Second},
+			RetryInterval:    Duration{1 * time.Second},
+			MaxRetryInterval: Duration{30 * time.Second},
+		},
+		Auth: AuthConfig{
+			TokenTTL: Duration{24 * time.Hour},
+			Session:  SessionConfig{CookieSecure: true, SameSite: "lax"},
+		},
+		I18n: I18nConfig{DefaultLanguage: i18n.LangID},
+		Log:  LogConfig{Level: "info", Format: "json"},
+		Tracing: TracingConfig{
+			ServiceName: "crud-app",
+			Exporter:    "none",
+			SampleRatio: 1,
+		},
+		Search: SearchConfig{
+			Backend:         "mongo",
+			ReindexInterval: Duration{time.Minute},
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Backend: "memory",
+			Policies: map[string]RateLimitPolicy{
+				"default": {Requests: 300, Window: Duration{time.Minute}, Key: "ip"},
+				"auth":    {Requests: 10, Window: Duration{time.Minute}, Key: "ip"},
+				"list":    {Requests: 120, Window: Duration{time.Minute}, Key: "user"},
+			},
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			AllowedHeaders: []string{"Authorization", "Content-Type", "Accept-Language", "X-Request-ID", "X-API-Key", "X-CSRF-Token"},
+			ExposedHeaders: []string{"X-Request-ID", "RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After", "Link"},
+			MaxAge:         Duration{10 * time.Minute},
+		},
+		Security: SecurityConfig{
+			// API hanya mengembalikan JSON, jadi tidak ada yang perlu dimuat.
+			ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+		},
+		API: APIConfig{
+			LegacyDeprecatedAt: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+			LegacySunset:       time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	switch profile {
+	case ProfileDev:
+		cfg.Auth.JWTSecret = "dev-insecure-secret"
+		cfg.Encryption = insecureEncryption("dev-insecure-encryption-key-0001", "dev-insecure-blind-index-key-001")
+		cfg.Log.Level = "debug"
+		cfg.CORS.AllowedOrigins = []string{"http://localhost:3001", "http://127.0.0.1:3001"}
+		cfg.CORS.AllowCredentials = true
+		// Frontend lokal berjalan di http, cookie Secure tidak akan dikirim.
+		cfg.Auth.Session.CookieSecure = false
+	case ProfileTest:
+		cfg.Mongo.Database = "alumni_db_test"
+		cfg.Mongo.QueryTimeout = Duration{5 * time.Second}
+		cfg.Auth.JWTSecret = "test-insecure-secret"
+		cfg.Encryption = insecureEncryption("test-insecure-encryption-key-001", "test-insecure-blind-index-key-01")
+	case ProfileProd:
+		// Secret, key enkripsi dan origin frontend wajib diisi dari luar,
+		// tidak ada default.
+		cfg.Security.HSTSMaxAge = Duration{365 * 24 * time.Hour}
+		cfg.Security.HSTSIncludeSubdomains = true
+	default:
+		return nil, fmt.Errorf("unknown profile %q (expected %s, %s or %s)", profile, ProfileDev, ProfileTest, ProfileProd)
+	}
+	return cfg, nil
+}
+
+// Validate memeriksa seluruh nilai dan mengembalikan semua kesalahan sekaligus.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	for name, d := range map[string]Duration{
+		"server.read_timeout":        c.Server.ReadTimeout,
+		"server.read_header_timeout": c.Server.ReadHeaderTimeout,
+		"server.write_timeout":       c.Server.WriteTimeout,
+		"server.idle_timeout":        c.Server.IdleTimeout,
+		"server.shutdown_timeout":    c.Server.ShutdownTimeout,
+	} {
+		if d.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", name))
+		}
+	}
+
+	if u, err := url.Parse(c.Mongo.URI); err != nil || (u.Scheme != "mongodb" && u.Scheme != "mongodb+srv") {
+		errs = append(errs, fmt.Errorf("mongo.uri must be a mongodb:// or mongodb+srv:// URI"))
+	}
+	if c.Mongo.Database == "" {
+		errs = append(errs, errors.New("mongo.database must not be empty"))
+	}
+	if c.Mongo.ConnectTimeout.Duration <= 0 {
+		errs = append(errs, errors.New("mongo.connect_timeout must be positive"))
+	}
+	if c.Mongo.QueryTimeout.Duration <= 0 {
+		errs = append(errs, errors.New("mongo.query_timeout must be positive"))
+	}
+	if c.Mongo.ListMaxTime.Duration <= 0 {
+		errs = append(errs, errors.New("mongo.list_max_time must be positive"))
+	}
+	if c.Mongo.PingInterval.Duration <= 0 || c.Mongo.RetryInterval.Duration <= 0 {
+		errs = append(errs, errors.New("mongo.ping_interval and mongo.retry_interval must be positive"))
+	}
+	if c.Mongo.MaxRetryInterval.Duration < c.Mongo.RetryInterval.Duration {
+		errs = append(errs, errors.New("mongo.max_retry_interval must not be smaller than mongo.retry_interval"))
+	}
+	for op, d := range c.Mongo.OperationTimeouts {
+		if d.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("mongo.operation_timeouts[%s] must be positive", op))
+		}
+	}
+
+	switch {
+	case c.Auth.JWTSecret == "":
+		errs = append(errs, errors.New("auth.jwt_secret must be set (JWT_SECRET)"))
+	case c.Profile == ProfileProd && len(c.Auth.JWTSecret) < 32:
+		errs = append(errs, errors.New("auth.jwt_secret must be at least 32 characters in prod"))
+	}
+	if len(c.Encryption.Keys) == 0 {
+		errs = append(errs, errors.New("encryption.keys must be set (ENCRYPTION_KEYS)"))
+	} else if _, err := fieldcrypt.ParseKeyring(c.Encryption.Keys, c.Encryption.ActiveKey, c.Encryption.BlindIndexKey); err != nil {
+		errs = append(errs, fmt.Errorf("encryption: %w", err))
+	}
+	if c.Auth.TokenTTL.Duration <= 0 {
+		errs = append(errs, errors.New("auth.token_ttl must be positive"))
+	}
+
+	switch c.Auth.Session.SameSite {
+	case "lax", "strict":
+	case "none":
+		if !c.Auth.Session.CookieSecure {
+			errs = append(errs, errors.New("auth.session.same_site none requires auth.session.cookie_secure"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("auth.session.same_site must be lax, strict or none, got %q", c.Auth.Session.SameSite))
+	}
+
+	if !contains(i18n.Supported(), c.I18n.DefaultLanguage) {
+		errs = append(errs, fmt.Errorf("i18n.default_language must be one of %v, got %q", i18n.Supported(), c.I18n.DefaultLanguage))
+	}
+
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("log.level must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		errs = append(errs, fmt.Errorf("log.format must be json or text, got %q", c.Log.Format))
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter must be none, stdout or otlp, got %q", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_ratio must be between 0 and 1, got %v", c.Tracing.SampleRatio))
+	}
+	if c.Tracing.ServiceName == "" {
+		errs = append(errs, errors.New("tracing.service_name must not be empty"))
+	}
+
+	if c.Search.Backend != "mongo" {
+		errs = append(errs, fmt.Errorf("search.backend must be mongo, got %q", c.Search.Backend))
+	}
+	if c.Search.ReindexInterval.Duration <= 0 {
+		errs = append(errs, errors.New("search.reindex_interval must be positive"))
+	}
+
+	if c.RateLimit.Backend != "memory" && c.RateLimit.Backend != "mongo" {
+		errs = append(errs, fmt.Errorf("rate_limit.backend must be memory or mongo, got %q", c.RateLimit.Backend))
+	}
+	for name, p := range c.RateLimit.Policies {
+		if p.Requests < 1 || p.Window.Duration <= 0 || p.Burst < 0 {
+			errs = append(errs, fmt.Errorf("rate_limit.policies.%s: requests and window must be positive", name))
+		}
+		if p.Key != "ip" && p.Key != "user" && p.Key != "api_key" {
+			errs = append(errs, fmt.Errorf("rate_limit.policies.%s.key must be ip, user or api_key, got %q", name, p.Key))
+		}
+	}
+
+	for _, o := range c.CORS.AllowedOrigins {
+		if o == "*" {
+			if c.CORS.AllowCredentials {
+				errs = append(errs, errors.New(`cors.allowed_origins must not contain "*" when cors.allow_credentials is true`))
+			}
+			continue
+		}
+		if u, err := url.Parse(strings.Replace(o, "*.", "", 1)); err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			errs = append(errs, fmt.Errorf("cors.allowed_origins: %q must be scheme://host[:port]", o))
+		}
+	}
+	if c.CORS.MaxAge.Duration < 0 {
+		errs = append(errs, errors.New("cors.max_age must not be negative"))
+	}
+	if c.Security.HSTSMaxAge.Duration < 0 {
+		errs = append(errs, errors.New("security.hsts_max_age must not be negative"))
+	}
+
+	if !c.API.LegacySunset.After(c.API.LegacyDeprecatedAt) {
+		errs = append(errs, errors.New("api.legacy_sunset must be after api.legacy_deprecated_at"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// OperationTimeoutMap mengembalikan timeout per operasi dalam bentuk time.Duration.
+func (m MongoConfig) OperationTimeoutMap() map[string]time.Duration {
+	out := make(map[string]time.Duration, len(m.OperationTimeouts))
+	for op, d := range m.OperationTimeouts {
+		out[op] = d.Duration
+	}
+	return out
+}
+
+// Redacted mengembalikan salinan config tanpa rahasia, aman untuk ditampilkan.
+func (c *Config) Redacted() Config {
+	out := *c
+	out.Auth.JWTSecret = redact(c.Auth.JWTSecret)
+	out.Encryption.Keys = make(map[string]string, len(c.Encryption.Keys))
+	for id, k := range c.Encryption.Keys {
+		out.Encryption.Keys[id] = redact(k)
+	}
+	out.Encryption.BlindIndexKey = redact(c.Encryption.BlindIndexKey)
+	if u, err := url.Parse(c.Mongo.URI); err == nil {
+		out.Mongo.URI = u.Redacted()
+	}
+	return out
+}
+
+// MarshalRedacted menghasilkan JSON dari Redacted.
+func (c *Config) MarshalRedacted() ([]byte, error) {
+	return json.MarshalIndent(c.Redacted(), "", "  ")
+}
+
+func redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "********"
+}
+
+// insecureEncryption adalah key enkripsi default untuk dev dan test.
+func insecureEncryption(key, indexKey string) EncryptionConfig {
+	enc := base64.StdEncoding.EncodeToString
+	return EncryptionConfig{
+		Keys:          map[string]string{"dev": enc([]byte(key))},
+		ActiveKey:     "dev",
+		BlindIndexKey: enc([]byte(indexKey)),
+	}
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
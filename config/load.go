@@ -0,0 +1,312 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Load membaca konfigurasi dari default profile, file opsional,
+// environment variable, lalu flag command line (args tanpa nama program).
+//
+// Profile wajib dipilih lewat flag -profile atau env APP_ENV; tanpa itu Load
+// gagal agar deployment tidak diam-diam berjalan dengan secret dev.
+// File dipilih dari flag -config atau env CONFIG_FILE; format ditentukan
+// dari ekstensinya (.yaml, .yml atau .toml).
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("crud-app", flag.ContinueOnError)
+	profile := fs.String("profile", "", "config profile: dev, test or prod (env APP_ENV)")
+	file := fs.String("config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+	port := fs.Int("port", 0, "HTTP port (env PORT)")
+	mongoURI := fs.String("mongo-uri", "", "MongoDB connection URI (env MONGO_URI)")
+	mongoDB := fs.String("mongo-db", "", "MongoDB database name (env MONGO_DATABASE)")
+	lang := fs.String("lang", "", "default response language (env DEFAULT_LANGUAGE)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	name := firstNonEmpty(*profile, os.Getenv("APP_ENV"))
+	if name == "" {
+		return nil, fmt.Errorf("no config profile selected: pass -profile or set APP_ENV (%s, %s or %s)", ProfileDev, ProfileTest, ProfileProd)
+	}
+	cfg, err := Defaults(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if path := firstNonEmpty(*file, os.Getenv("CONFIG_FILE")); path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	// Flag hanya menimpa jika benar-benar diberikan.
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Server.Port = *port
+		case "mongo-uri":
+			cfg.Mongo.URI = *mongoURI
+		case "mongo-db":
+			cfg.Mongo.Database = *mongoDB
+		case "lang":
+			cfg.I18n.DefaultLanguage = *lang
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration (profile %s):\n%w", cfg.Profile, err)
+	}
+	return cfg, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file extension %q (use .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func applyEnv(cfg *Config) error {
+	if v := os.Getenv("PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("PORT: %q is not a number", v)
+		}
+		cfg.Server.Port = port
+	}
+	for key, dst := range map[string]*Duration{
+		"HTTP_READ_TIMEOUT":        &cfg.Server.ReadTimeout,
+		"HTTP_READ_HEADER_TIMEOUT": &cfg.Server.ReadHeaderTimeout,
+		"HTTP_WRITE_TIMEOUT":       &cfg.Server.WriteTimeout,
+		"HTTP_IDLE_TIMEOUT":        &cfg.Server.IdleTimeout,
+		"SHUTDOWN_TIMEOUT":         &cfg.Server.ShutdownTimeout,
+		"MONGO_PING_INTERVAL":      &cfg.Mongo.PingInterval,
+		"MONGO_RETRY_INTERVAL":     &cfg.Mongo.RetryInterval,
+		"MONGO_MAX_RETRY_INTERVAL": &cfg.Mongo.MaxRetryInterval,
+	} {
+		if err := envDuration(key, dst); err != nil {
+			return err
+		}
+	}
+	if v := os.Getenv("MONGO_URI"); v != "" {
+		cfg.Mongo.URI = v
+	}
+	if v := os.Getenv("MONGO_DATABASE"); v != "" {
+		cfg.Mongo.Database = v
+	}
+	if err := envDuration("MONGO_CONNECT_TIMEOUT", &cfg.Mongo.ConnectTimeout); err != nil {
+		return err
+	}
+	if err := envDuration("MONGO_TIMEOUT", &cfg.Mongo.QueryTimeout); err != nil {
+		return err
+	}
+	if err := envDuration("MONGO_LIST_MAX_TIME", &cfg.Mongo.ListMaxTime); err != nil {
+		return err
+	}
+	if v := os.Getenv("MONGO_OPERATION_TIMEOUTS"); v != "" {
+		perOp, err := parseOperationTimeouts(v)
+		if err != nil {
+			return fmt.Errorf("MONGO_OPERATION_TIMEOUTS: %w", err)
+		}
+		cfg.Mongo.OperationTimeouts = perOp
+	}
+	if v := os.Getenv("JWT_SECRET"); v != "" {
+		cfg.Auth.JWTSecret = v
+	}
+	if v := os.Getenv("ENCRYPTION_KEYS"); v != "" {
+		keys, err := parseKeys(v)
+		if err != nil {
+			return fmt.Errorf("ENCRYPTION_KEYS: %w", err)
+		}
+		cfg.Encryption.Keys = keys
+	}
+	if v := os.Getenv("ENCRYPTION_ACTIVE_KEY"); v != "" {
+		cfg.Encryption.ActiveKey = v
+	}
+	if v := os.Getenv("ENCRYPTION_BLIND_INDEX_KEY"); v != "" {
+		cfg.Encryption.BlindIndexKey = v
+	}
+	if v := os.Getenv("SESSION_COOKIE_SECURE"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("SESSION_COOKIE_SECURE: %q is not a boolean", v)
+		}
+		cfg.Auth.Session.CookieSecure = b
+	}
+	if v := os.Getenv("SESSION_SAMESITE"); v != "" {
+		cfg.Auth.Session.SameSite = strings.ToLower(v)
+	}
+	if err := envDuration("JWT_TTL", &cfg.Auth.TokenTTL); err != nil {
+		return err
+	}
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		cfg.Log.Level = strings.ToLower(v)
+	}
+	if v := os.Getenv("LOG_FORMAT"); v != "" {
+		cfg.Log.Format = strings.ToLower(v)
+	}
+	if v := os.Getenv("TRACING_EXPORTER"); v != "" {
+		cfg.Tracing.Exporter = strings.ToLower(v)
+	}
+	if v := os.Getenv("OTEL_SERVICE_NAME"); v != "" {
+		cfg.Tracing.ServiceName = v
+	}
+	if v := os.Getenv("OTLP_ENDPOINT"); v != "" {
+		cfg.Tracing.OTLPEndpoint = v
+	}
+	if v := os.Getenv("OTLP_INSECURE"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("OTLP_INSECURE: %q is not a boolean", v)
+		}
+		cfg.Tracing.OTLPInsecure = b
+	}
+	if v := os.Getenv("TRACING_SAMPLE_RATIO"); v != "" {
+		ratio, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("TRACING_SAMPLE_RATIO: %q is not a number", v)
+		}
+		cfg.Tracing.SampleRatio = ratio
+	}
+	if v := os.Getenv("RATE_LIMIT_ENABLED"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("RATE_LIMIT_ENABLED: %q is not a boolean", v)
+		}
+		cfg.RateLimit.Enabled = b
+	}
+	if v := os.Getenv("SEARCH_BACKEND"); v != "" {
+		cfg.Search.Backend = strings.ToLower(v)
+	}
+	if v := os.Getenv("RATE_LIMIT_BACKEND"); v != "" {
+		cfg.RateLimit.Backend = strings.ToLower(v)
+	}
+	if v := os.Getenv("RATE_LIMIT_TRUST_PROXY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("RATE_LIMIT_TRUST_PROXY: %q is not a boolean", v)
+		}
+		cfg.RateLimit.TrustForwardedFor = b
+	}
+	if v := os.Getenv("CORS_ALLOWED_ORIGINS"); v != "" {
+		cfg.CORS.AllowedOrigins = splitList(v)
+	}
+	if v := os.Getenv("CORS_ALLOW_CREDENTIALS"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("CORS_ALLOW_CREDENTIALS: %q is not a boolean", v)
+		}
+		cfg.CORS.AllowCredentials = b
+	}
+	if err := envDuration("HSTS_MAX_AGE", &cfg.Security.HSTSMaxAge); err != nil {
+		return err
+	}
+	if v := os.Getenv("LEGACY_SUNSET"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return fmt.Errorf("LEGACY_SUNSET: %q is not an RFC 3339 time", v)
+		}
+		cfg.API.LegacySunset = t
+	}
+	if v := os.Getenv("DEFAULT_LANGUAGE"); v != "" {
+		cfg.I18n.DefaultLanguage = strings.ToLower(v)
+	}
+	return nil
+}
+
+func envDuration(key string, dst *Duration) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a valid duration", key, v)
+	}
+	dst.Duration = d
+	return nil
+}
+
+// parseOperationTimeouts membaca format "alumni.GetAlumni=5s,user.GetByID=2s".
+func parseOperationTimeouts(s string) (map[string]Duration, error) {
+	out := map[string]Duration{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		op, val, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid operation timeout %q, expected op=duration", pair)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration for %q: %q", op, val)
+		}
+		out[strings.TrimSpace(op)] = Duration{d}
+	}
+	return out, nil
+}
+
+// parseKeys membaca format "k2=base64,k1=base64".
+func parseKeys(s string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, key, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, errors.New("invalid entry, expected id=base64")
+		}
+		out[strings.TrimSpace(id)] = strings.TrimSpace(key)
+	}
+	return out, nil
+}
+
+// splitList membaca daftar dipisah koma, mengabaikan item kosong.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// clearEnv mengosongkan env yang dibaca Load agar environment mesin tidak
+// ikut memengaruhi test.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{"APP_ENV", "CONFIG_FILE", "PORT", "MONGO_URI", "MONGO_DATABASE", "DEFAULT_LANGUAGE", "JWT_SECRET", "ENCRYPTION_KEYS", "ENCRYPTION_ACTIVE_KEY", "ENCRYPTION_BLIND_INDEX_KEY"} {
+		t.Setenv(key, "")
+	}
+}
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRequiresProfile(t *testing.T) {
+	clearEnv(t)
+	_, err := Load(nil)
+	if err == nil || !strings.Contains(err.Error(), "no config profile selected") {
+		t.Fatalf("Load without profile: err = %v, want no profile error", err)
+	}
+
+	t.Setenv("APP_ENV", ProfileTest)
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != ProfileTest {
+		t.Errorf("profile = %q, want %q", cfg.Profile, ProfileTest)
+	}
+
+	// flag menang atas APP_ENV
+	cfg, err = Load([]string{"-profile", ProfileDev})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != ProfileDev {
+		t.Errorf("profile = %q, want %q", cfg.Profile, ProfileDev)
+	}
+}
+
+func TestLoadProdWithoutSecretsFails(t *testing.T) {
+	clearEnv(t)
+	_, err := Load([]string{"-profile", ProfileProd})
+	if err == nil {
+		t.Fatal("prod profile without secrets loaded, want error")
+	}
+	for _, want := range []string{"auth.jwt_secret", "encryption.keys"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	yamlFile := writeConfig(t, "app.yaml", "server:\n  port: 4000\nmongo:\n  database: from_file\ni18n:\n  default_language: en\n")
+	tomlFile := writeConfig(t, "app.toml", "[server]\nport = 4000\n[mongo]\ndatabase = \"from_file\"\n")
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		port     int
+		database string
+		lang     string
+	}{
+		{
+			name: "defaults",
+			port: 3000, database: "alumni_db_test", lang: "id",
+		},
+		{
+			name: "yaml file over defaults",
+			args: []string{"-config", yamlFile},
+			port: 4000, database: "from_file", lang: "en",
+		},
+		{
+			name: "toml file from CONFIG_FILE",
+			env:  map[string]string{"CONFIG_FILE": tomlFile},
+			port: 4000, database: "from_file", lang: "id",
+		},
+		{
+			name: "env over file",
+			env:  map[string]string{"PORT": "5000", "MONGO_DATABASE": "from_env"},
+			args: []string{"-config", yamlFile},
+			port: 5000, database: "from_env", lang: "en",
+		},
+		{
+			name: "flag over env and file",
+			env:  map[string]string{"PORT": "5000", "MONGO_DATABASE": "from_env", "DEFAULT_LANGUAGE": "id"},
+			args: []string{"-config", yamlFile, "-port", "6000", "-lang", "en"},
+			port: 6000, database: "from_env", lang: "en",
+		},
+		{
+			name: "flag -config over CONFIG_FILE",
+			env:  map[string]string{"CONFIG_FILE": filepath.Join(t.TempDir(), "missing.yaml")},
+			args: []string{"-config", tomlFile},
+			port: 4000, database: "from_file", lang: "id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := Load(append([]string{"-profile", ProfileTest}, tt.args...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Server.Port != tt.port {
+				t.Errorf("port = %d, want %d", cfg.Server.Port, tt.port)
+			}
+			if cfg.Mongo.Database != tt.database {
+				t.Errorf("database = %q, want %q", cfg.Mongo.Database, tt.database)
+			}
+			if cfg.I18n.DefaultLanguage != tt.lang {
+				t.Errorf("language = %q, want %q", cfg.I18n.DefaultLanguage, tt.lang)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsBadSources(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{"unknown profile", nil, []string{"-profile", "staging"}, "unknown profile"},
+		{"unknown file extension", nil, []string{"-profile", ProfileTest, "-config", writeConfig(t, "app.json", "{}")}, "unsupported config file extension"},
+		{"bad env number", map[string]string{"PORT": "abc"}, []string{"-profile", ProfileTest}, "PORT"},
+		{"invalid value from env", map[string]string{"PORT": "70000"}, []string{"-profile", ProfileTest}, "server.port"},
+		{"bad encryption keys", map[string]string{"ENCRYPTION_KEYS": "k1"}, []string{"-profile", ProfileTest}, "ENCRYPTION_KEYS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := Load(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want mention of %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"crud-app/app/logger"
+	"crud-app/app/metrics"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// commandMonitor mencatat setiap perintah Mongo ke log (bersama request_id
+// dari context query) dan ke metric per collection.
+func commandMonitor() *event.CommandMonitor {
+	// Event Succeeded/Failed tidak membawa nama collection, jadi disimpan
+	// dari event Started berdasarkan RequestID driver.
+	var collections sync.Map
+
+	finish := func(requestID int64) string {
+		coll, _ := collections.LoadAndDelete(requestID)
+		name, _ := coll.(string)
+		return name
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			if coll := commandCollection(e); coll != "" {
+				collections.Store(e.RequestID, coll)
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			coll := finish(e.RequestID)
+			observe(ctx, coll, e.CommandName, e.DatabaseName, e.Duration, "")
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			coll := finish(e.RequestID)
+			observe(ctx, coll, e.CommandName, e.DatabaseName, e.Duration, e.Failure)
+		},
+	}
+}
+
+func observe(ctx context.Context, coll, command, database string, d time.Duration, failure string) {
+	if coll != "" {
+		metrics.ObserveMongo(coll, command, d, failure != "")
+	}
+
+	attrs := []slog.Attr{
+		slog.String("command", command),
+		slog.String("database", database),
+		slog.String("collection", coll),
+		slog.Float64("duration_ms", float64(d.Microseconds())/1000),
+	}
+	if failure != "" {
+		attrs = append(attrs, slog.String("error", failure))
+		logger.FromContext(ctx).LogAttrs(ctx, slog.LevelWarn, "mongo command failed", attrs...)
+		return
+	}
+	logger.FromContext(ctx).LogAttrs(ctx, slog.LevelDebug, "mongo command", attrs...)
+}
+
+// commandCollection mengambil nama collection dari perintah, misalnya
+// {find: "alumni", ...} atau {getMore: <id>, collection: "alumni"}.
+func commandCollection(e *event.CommandStartedEvent) string {
+	if v, err := e.Command.IndexErr(0); err == nil {
+		if name, ok := v.Value().StringValueOK(); ok {
+			return name
+		}
+	}
+	if name, ok := e.Command.Lookup("collection").StringValueOK(); ok {
+		return name
+	}
+	return ""
+}
+
+// combineMonitors meneruskan setiap event ke semua monitor, karena driver
+// hanya menerima satu CommandMonitor.
+func combineMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				if m.Started != nil {
+					m.Started(ctx, e)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				if m.Succeeded != nil {
+					m.Succeeded(ctx, e)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				if m.Failed != nil {
+					m.Failed(ctx, e)
+				}
+			}
+		},
+	}
+}
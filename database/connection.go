@@ -2,39 +2,33 @@ package database
 
 import (
 	"context"
+	"crud-app/config"
 	"fmt"
-	"log"
-	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
-func ConnectDB() *mongo.Client {
-	// MongoDB connection string
-	mongoURI := "mongodb://localhost:27017"
-	
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// ConnectDB membuat client Mongo tanpa menunggu server siap. Error hanya
+// dikembalikan untuk konfigurasi yang salah; ketersediaan server dipantau
+// oleh Monitor sehingga aplikasi tetap jalan (degraded) saat Mongo mati.
+func ConnectDB(cfg config.MongoConfig) (*mongo.Client, error) {
+	opts := options.Client().
+		ApplyURI(cfg.URI).
+		SetConnectTimeout(cfg.ConnectTimeout.Duration).
+		SetServerSelectionTimeout(cfg.ConnectTimeout.Duration).
+		SetMonitor(combineMonitors(commandMonitor(), otelmongo.NewMonitor()))
 
 	// Create MongoDB client
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	client, err := mongo.Connect(context.Background(), opts)
 	if err != nil {
-		log.Fatal("Failed to connect to MongoDB: ", err)
+		return nil, fmt.Errorf("failed to create MongoDB client: %w", err)
 	}
-
-	// Verify connection
-	err = client.Ping(ctx, nil)
-	if err != nil {
-		log.Fatal("MongoDB unreachable: ", err)
-	}
-
-	fmt.Println("MongoDB connected ✅")
-	return client
+	return client, nil
 }
 
 // GetDatabase returns the database instance
-func GetDatabase(client *mongo.Client) *mongo.Database {
-	return client.Database("alumni_db")
+func GetDatabase(client *mongo.Client, name string) *mongo.Database {
+	return client.Database(name)
 }
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexes adalah index yang dibutuhkan query di repository, per collection.
+var indexes = map[string][]mongo.IndexModel{
+	"users": {
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetName("username_1")},
+	},
+	"alumni": {
+		{Keys: bson.D{{Key: "nama", Value: 1}}, Options: options.Index().SetName("nama_1")},
+		{Keys: bson.D{{Key: "jurusan", Value: 1}, {Key: "angkatan", Value: 1}}, Options: options.Index().SetName("jurusan_1_angkatan_1")},
+		{Keys: bson.D{{Key: "email_bidx", Value: 1}}, Options: options.Index().SetName("email_bidx_1")},
+		{Keys: bson.D{{Key: "encryption.key_id", Value: 1}}, Options: options.Index().SetName("encryption_key_id_1")},
+		searchTermsIndex,
+		{Keys: bson.D{{Key: "search_version", Value: 1}}, Options: options.Index().SetName("search_version_1")},
+	},
+	"rate_limits": {
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0)},
+	},
+	"pekerjaan_alumni": {
+		{Keys: bson.D{{Key: "alumni_id", Value: 1}, {Key: "is_deleted", Value: 1}}, Options: options.Index().SetName("alumni_id_1_is_deleted_1")},
+		{Keys: bson.D{{Key: "is_deleted", Value: 1}}, Options: options.Index().SetName("is_deleted_1")},
+		searchTermsIndex,
+		{Keys: bson.D{{Key: "search_version", Value: 1}}, Options: options.Index().SetName("search_version_1")},
+	},
+}
+
+// searchTermsIndex adalah text index full-text search. Term sudah di-stem
+// oleh package search, jadi Mongo tidak boleh men-stem lagi.
+var searchTermsIndex = mongo.IndexModel{
+	Keys:    bson.D{{Key: "search_terms", Value: "text"}},
+	Options: options.Index().SetName("search_terms_text").SetDefaultLanguage("none"),
+}
+
+// EnsureIndexes membuat index yang belum ada. Aman dipanggil berulang kali.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	for coll, models := range indexes {
+		if _, err := db.Collection(coll).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("create indexes on %s: %w", coll, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"crud-app/config"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Monitor memantau koneksi Mongo di background. Jika Mongo mati, monitor
+// mencoba lagi dengan backoff; begitu tersambung, index dibuat sekali.
+type Monitor struct {
+	client *mongo.Client
+	db     *mongo.Database
+	cfg    config.MongoConfig
+
+	mu           sync.RWMutex
+	lastErr      error
+	lastCheck    time.Time
+	indexesReady bool
+	indexErr     error
+}
+
+func NewMonitor(client *mongo.Client, db *mongo.Database, cfg config.MongoConfig) *Monitor {
+	return &Monitor{
+		client:  client,
+		db:      db,
+		cfg:     cfg,
+		lastErr: errors.New("not checked yet"),
+	}
+}
+
+// Run adalah loop worker; berhenti ketika ctx dibatalkan.
+func (m *Monitor) Run(ctx context.Context) error {
+	backoff := m.cfg.RetryInterval.Duration
+	for {
+		wait := m.cfg.PingInterval.Duration
+		if err := m.check(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			slog.Warn("MongoDB not ready", "retry_in", backoff.String(), "error", err)
+			wait = backoff
+			backoff *= 2
+			if backoff > m.cfg.MaxRetryInterval.Duration {
+				backoff = m.cfg.MaxRetryInterval.Duration
+			}
+		} else {
+			backoff = m.cfg.RetryInterval.Duration
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (m *Monitor) check(ctx context.Context) error {
+	err := m.Ping(ctx)
+
+	m.mu.Lock()
+	wasDown := m.lastErr != nil
+	m.lastErr, m.lastCheck = err, time.Now()
+	needIndexes := err == nil && !m.indexesReady
+	m.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if wasDown {
+		slog.Info("MongoDB connected")
+	}
+
+	if needIndexes {
+		ictx, cancel := context.WithTimeout(ctx, m.cfg.ConnectTimeout.Duration)
+		defer cancel()
+		ierr := EnsureIndexes(ictx, m.db)
+
+		m.mu.Lock()
+		m.indexesReady, m.indexErr = ierr == nil, ierr
+		m.mu.Unlock()
+		return ierr
+	}
+	return nil
+}
+
+// Ping memeriksa koneksi saat ini juga, tanpa menunggu loop.
+func (m *Monitor) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.ConnectTimeout.Duration)
+	defer cancel()
+	return m.client.Ping(ctx, nil)
+}
+
+// Connected melaporkan hasil pemeriksaan terakhir dari loop.
+func (m *Monitor) Connected() (bool, time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastErr == nil, m.lastCheck, m.lastErr
+}
+
+// IndexesReady mengembalikan nil jika index sudah berhasil dibuat.
+func (m *Monitor) IndexesReady() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.indexesReady {
+		return nil
+	}
+	if m.indexErr != nil {
+		return m.indexErr
+	}
+	return errors.New("index bootstrap has not run yet")
+}
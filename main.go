@@ -1,39 +1,340 @@
 package main
 
 import (
+	"context"
+	middleware "crud-app/Middleware"
 	service "crud-app/app/Service"
+	"crud-app/app/fieldcrypt"
+	"crud-app/app/i18n"
+	"crud-app/app/logger"
+	"crud-app/app/openapi"
+	"crud-app/app/ratelimit"
 	"crud-app/app/repository"
+	"crud-app/app/search"
+	"crud-app/app/tracing"
+	"crud-app/app/worker"
+	"crud-app/config"
 	"crud-app/database"
 	"crud-app/routes"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 )
 
+// readinessTimeout membatasi lama /readyz agar probe orchestrator tidak menggantung.
+const readinessTimeout = 2 * time.Second
+
 func main() {
-	mongoClient := database.ConnectDB()
-	db := database.GetDatabase(mongoClient)
+	if err := run(); err != nil {
+		slog.Error("startup failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run() (err error) {
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		return err
+	}
+
+	if err := logger.Setup(os.Stdout, cfg.Log.Level, cfg.Log.Format); err != nil {
+		return err
+	}
+	if cfg.Profile == config.ProfileDev {
+		slog.Warn("DEV PROFILE ACTIVE: built-in JWT secret and encryption keys are public, never use this profile in production")
+	}
+
+	if err := i18n.SetDefaultLanguage(cfg.I18n.DefaultLanguage); err != nil {
+		return err
+	}
+
+	// Tracing dipasang sebelum Mongo dan router agar instrumentasinya
+	// memakai TracerProvider yang benar.
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: cfg.Tracing.ServiceName,
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.OTLPEndpoint,
+		Insecure:    cfg.Tracing.OTLPInsecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Selama server belum melayani, startup yang gagal tetap menghentikan
+	// worker, memutus Mongo dan mengirim span yang sudah terkumpul.
+	var (
+		workers     *worker.Group
+		mongoClient *mongo.Client
+		serving     bool
+	)
+	defer func() {
+		if !serving {
+			err = errors.Join(err, shutdown(cfg, nil, workers, mongoClient, shutdownTracing))
+		}
+	}()
+
+	// SIGINT / SIGTERM memulai graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	mongoClient, err = database.ConnectDB(cfg.Mongo)
+	if err != nil {
+		return err
+	}
+	db := database.GetDatabase(mongoClient, cfg.Mongo.Database)
+
+	// Mongo dipantau di background: service tetap jalan (degraded) saat
+	// Mongo belum bisa dihubungi dan akan tersambung ulang otomatis.
+	workers = worker.NewGroup(context.Background())
+	monitor := database.NewMonitor(mongoClient, db, cfg.Mongo)
+	workers.Go("mongo-monitor", monitor.Run)
+
+	limiter, err := newRateLimiter(cfg.RateLimit, db, workers)
+	if err != nil {
+		return err
+	}
+
+	// Timeout query Mongo, bisa diatur per operasi
+	timeouts := repository.Timeouts{
+		Default:      cfg.Mongo.QueryTimeout.Duration,
+		PerOperation: cfg.Mongo.OperationTimeoutMap(),
+		ListMaxTime:  cfg.Mongo.ListMaxTime.Duration,
+	}
+
+	// Key enkripsi kontak alumni (sudah divalidasi saat config dimuat)
+	keys, err := fieldcrypt.ParseKeyring(cfg.Encryption.Keys, cfg.Encryption.ActiveKey, cfg.Encryption.BlindIndexKey)
+	if err != nil {
+		return err
+	}
 
 	// repositories
-	userRepo := repository.NewUserRepository(db)
-	alumniRepo := repository.NewAlumniRepository(db)
-	pekerjaanRepo := repository.NewPekerjaanRepository(db)
+	userRepo := repository.NewUserRepository(db, timeouts)
+	alumniRepo := repository.NewAlumniRepository(db, timeouts, keys)
+	pekerjaanRepo := repository.NewPekerjaanRepository(db, timeouts)
+	searchRepo := repository.NewSearchRepository(db, timeouts, keys)
+
+	// Indexer mengisi search_terms dokumen lama dan dokumen yang ditulis di luar API
+	workers.Go("search-indexer", func(ctx context.Context) error {
+		return search.RunIndexer(ctx, searchRepo, cfg.Search.ReindexInterval.Duration)
+	})
 
 	// Service
-	authService := service.NewAuthService(userRepo)
+	authService := service.NewAuthService(userRepo, cfg.Auth)
 	alumniService := service.NewAlumniService(alumniRepo)
 	PekerjaanService := service.NewPekerjaanService(pekerjaanRepo)
 	userService := service.NewUserHandler(userRepo)
+	searchService := service.NewSearchService(searchRepo)
+	adminService := service.NewAdminService(cfg)
+	spec, err := openapi.Load()
+	if err != nil {
+		return err
+	}
+	docsService := service.NewDocsService(spec)
+	if !docsService.HasRedoc() {
+		slog.Warn("redoc bundle not embedded, /docs serves a placeholder (run go generate ./app/Service)")
+	}
+	healthService := service.NewHealthService(readinessTimeout,
+		service.HealthCheck{Name: "mongo", Check: func(ctx context.Context) (interface{}, error) {
+			// Jika monitor sudah tahu Mongo mati, jangan menunggu timeout lagi.
+			if ok, _, err := monitor.Connected(); !ok {
+				return nil, err
+			}
+			return nil, monitor.Ping(ctx)
+		}},
+		service.HealthCheck{Name: "indexes", Check: func(ctx context.Context) (interface{}, error) {
+			return nil, monitor.IndexesReady()
+		}},
+		service.HealthCheck{Name: "workers", Check: func(ctx context.Context) (interface{}, error) {
+			states := workers.States()
+			for name, st := range states {
+				if st.Status != worker.StatusRunning {
+					return states, fmt.Errorf("worker %s is %s", name, st.Status)
+				}
+			}
+			return states, nil
+		}},
+	)
 	r := mux.NewRouter()
+	r.Use(otelmux.Middleware(cfg.Tracing.ServiceName))
+
+	routes.Register(r, routes.Deps{
+		Config:    cfg,
+		UserRepo:  userRepo,
+		Limiter:   limiter,
+		Auth:      authService,
+		Alumni:    alumniService,
+		Pekerjaan: PekerjaanService,
+		Users:     userService,
+		Search:    searchService,
+		Admin:     adminService,
+		Health:    healthService,
+		Docs:      docsService,
+	})
+	if err := checkRouter(cfg, spec, r); err != nil {
+		return err
+	}
+
+	// Urutan: request ID paling luar agar tersedia untuk log dan handler.
+	// CORS di luar rate limit agar preflight tidak memakan kuota dan
+	// response 429 tetap bisa dibaca frontend.
+	var handler http.Handler = r
+	handler = middleware.RateLimit(limiter, "default", handler)
+	handler = middleware.CORS(middleware.CORSOptions{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowedMethods:   cfg.CORS.AllowedMethods,
+		AllowedHeaders:   cfg.CORS.AllowedHeaders,
+		ExposedHeaders:   cfg.CORS.ExposedHeaders,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           cfg.CORS.MaxAge.Duration,
+	}, handler)
+	handler = middleware.SecurityHeaders(middleware.SecurityOptions{
+		ContentSecurityPolicy: cfg.Security.ContentSecurityPolicy,
+		HSTSMaxAge:            cfg.Security.HSTSMaxAge.Duration,
+		HSTSIncludeSubdomains: cfg.Security.HSTSIncludeSubdomains,
+	}, handler)
+	handler = middleware.Metrics(r, handler)
+	handler = middleware.AccessLog(r, handler)
+	handler = middleware.RequestID(handler)
 
-	routes.UserRoutes(r, PekerjaanService, alumniService, authService, &userRepo, userService)
-	// Run server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000"
+	srv := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:           handler,
+		ReadTimeout:       cfg.Server.ReadTimeout.Duration,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout.Duration,
+		WriteTimeout:      cfg.Server.WriteTimeout.Duration,
+		IdleTimeout:       cfg.Server.IdleTimeout.Duration,
+	}
+
+	// Listen dulu agar error seperti port terpakai langsung ketahuan
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", srv.Addr, err)
+	}
+	serving = true
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("server running", "addr", srv.Addr, "profile", cfg.Profile)
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+		close(serveErr)
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("shutdown signal received, draining requests")
+	case err := <-serveErr:
+		runErr = fmt.Errorf("http server: %w", err)
+	}
+
+	return errors.Join(runErr, shutdown(cfg, srv, workers, mongoClient, shutdownTracing))
+}
+
+// newRateLimiter membuat limiter dari config. Jika rate limit dimatikan,
+// limiter tanpa policy dikembalikan sehingga middleware tidak membatasi apa pun.
+func newRateLimiter(cfg config.RateLimitConfig, db *mongo.Database, workers *worker.Group) (*ratelimit.Limiter, error) {
+	var policies []ratelimit.Policy
+	longest := time.Minute
+	if cfg.Enabled {
+		for name, p := range cfg.Policies {
+			policies = append(policies, ratelimit.Policy{
+				Name:     name,
+				Requests: p.Requests,
+				Window:   p.Window.Duration,
+				Burst:    p.Burst,
+				Key:      p.Key,
+			})
+			if p.Window.Duration > longest {
+				longest = p.Window.Duration
+			}
+		}
+	}
+
+	var store ratelimit.Store
+	if cfg.Backend == "mongo" {
+		store = ratelimit.NewMongoStore(db)
+	} else {
+		mem := ratelimit.NewMemoryStore(2 * longest)
+		if cfg.Enabled {
+			workers.Go("ratelimit-janitor", mem.Run)
+		}
+		store = mem
+	}
+
+	limiter, err := ratelimit.NewLimiter(store, policies...)
+	if err != nil {
+		return nil, err
+	}
+	limiter.TrustProxy = cfg.TrustForwardedFor
+	return limiter, nil
+}
+
+// shutdown menunggu request yang sedang berjalan, menghentikan background
+// worker, memutus koneksi Mongo lalu mengirim sisa span, semuanya dalam
+// batas shutdown_timeout. srv, workers dan mongoClient boleh nil jika
+// startup gagal sebelum resource itu dibuat.
+func shutdown(cfg *config.Config, srv *http.Server, workers *worker.Group, mongoClient *mongo.Client, shutdownTracing func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Duration)
+	defer cancel()
+
+	var errs []error
+	if srv != nil {
+		if err := srv.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("http shutdown: %w", err))
+		}
+	}
+	if workers != nil {
+		if err := workers.Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if mongoClient != nil {
+		if err := mongoClient.Disconnect(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("mongo disconnect: %w", err))
+		}
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("flush traces: %w", err))
+	}
+
+	if len(errs) == 0 && srv != nil {
+		slog.Info("server stopped cleanly")
+	}
+	return errors.Join(errs...)
+}
+
+// checkRouter memastikan setiap route bisa dicapai (tidak tertutup route
+// lain) dan terdokumentasi di OpenAPI. Di luar prod masalah menggagalkan
+// startup agar langsung ketahuan saat development; di prod cukup dicatat.
+func checkRouter(cfg *config.Config, spec *openapi.Spec, r *mux.Router) error {
+	shadowed, err := routes.Unreachable(r)
+	if err != nil {
+		return fmt.Errorf("route reachability check: %w", err)
+	}
+	missing, stale, err := spec.Check(r)
+	if err != nil {
+		return fmt.Errorf("openapi route check: %w", err)
+	}
+	if len(shadowed) == 0 && len(missing) == 0 && len(stale) == 0 {
+		return nil
+	}
+	if cfg.Profile == config.ProfileProd {
+		slog.Warn("router check failed", "unreachable", shadowed, "undocumented", missing, "stale", stale)
+		return nil
 	}
-	log.Println("🚀 Server running at http://localhost:" + port)
-	http.ListenAndServe(":"+port, r)
+	return fmt.Errorf("router check failed: unreachable %v, undocumented %v, stale %v", shadowed, missing, stale)
 }
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	middleware "crud-app/Middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Legacy memasang alias lama di root path agar client lama tetap jalan
+// selama masa transisi. Setiap response membawa header Deprecation dan
+// Sunset serta Link ke route /api/v1 penggantinya. Jangan tambah route
+// baru di sini.
+func Legacy(r *mux.Router, d Deps) {
+	deprecated := func(successor string, h http.Handler) http.Handler {
+		return middleware.Deprecated(d.Config.API.LegacyDeprecatedAt, d.Config.API.LegacySunset, successor, h)
+	}
+
+	r.Handle("/register", deprecated("/api/v1/register", d.public(d.Auth.Register))).Methods("POST")
+	r.Handle("/login", deprecated("/api/v1/login", d.public(d.Auth.Login))).Methods("POST")
+	r.Handle("/logout", deprecated("/api/v1/logout", http.HandlerFunc(d.Auth.Logout))).Methods("POST")
+	r.Handle("/admin/config", deprecated("/api/v1/admin/config", d.admin(d.Admin.GetConfig))).Methods("GET")
+
+	r.Handle("/alumni", deprecated("/api/v1/alumni", d.list(d.Alumni.GetAlumni))).Methods("GET")
+	r.Handle("/alumni", deprecated("/api/v1/alumni", d.admin(d.Alumni.Create))).Methods("POST")
+	r.Handle("/alumni/{id}", deprecated("/api/v1/alumni/{id}", d.auth(d.Alumni.GetByID))).Methods("GET")
+	r.Handle("/alumni/{id}", deprecated("/api/v1/alumni/{id}", d.admin(d.Alumni.Update))).Methods("PUT")
+	r.Handle("/alumni/{id}", deprecated("/api/v1/alumni/{id}", d.admin(d.Alumni.Delete))).Methods("DELETE")
+
+	r.Handle("/pekerjaan", deprecated("/api/v1/pekerjaan", d.list(d.Pekerjaan.GetPekerjaan))).Methods("GET")
+	r.Handle("/pekerjaan", deprecated("/api/v1/pekerjaan", d.admin(d.Pekerjaan.Create))).Methods("POST")
+	// GET /pekerjaan/{id} dulu selalu berarti ID alumni.
+	r.Handle("/pekerjaan/{id}", deprecated("/api/v1/alumni/{id}/pekerjaan", d.auth(d.Pekerjaan.GetByAlumni))).Methods("GET")
+	r.Handle("/pekerjaan/{id}", deprecated("/api/v1/pekerjaan/{id}", d.admin(d.Pekerjaan.Update))).Methods("PUT")
+	r.Handle("/pekerjaan/{id}", deprecated("/api/v1/pekerjaan/{id}", d.auth(d.Pekerjaan.SoftDeletePekerjaan))).Methods("DELETE")
+
+	r.Handle("/trash/pekerjaan", deprecated("/api/v1/trash/pekerjaan", d.list(d.Pekerjaan.GetTrash))).Methods("GET")
+	r.Handle("/trash/pekerjaan/{id}/restore", deprecated("/api/v1/trash/pekerjaan/{id}/restore", d.auth(d.Pekerjaan.RestorePekerjaan))).Methods("PUT")
+	r.Handle("/trash/pekerjaan/{id}/hard-delete", deprecated("/api/v1/trash/pekerjaan/{id}/hard-delete", d.auth(d.Pekerjaan.HardDeletePekerjaan))).Methods("DELETE")
+
+	r.Handle("/users", deprecated("/api/v1/users", d.list(d.Users.GetUsers))).Methods("GET")
+	r.Handle("/Users/{id}", deprecated("/api/v1/users/{id}", d.auth(d.Users.SoftDeleteUser))).Methods("DELETE")
+}
@@ -0,0 +1,58 @@
+package routes
+
+import (
+	service "crud-app/app/Service"
+	"crud-app/app/openapi"
+	"crud-app/app/ratelimit"
+	"crud-app/config"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestRouter memasang semua route seperti main.go. Service tidak
+// dipanggil, jadi cukup nilai kosong.
+func newTestRouter(t *testing.T) *mux.Router {
+	t.Helper()
+	cfg, err := config.Defaults(config.ProfileTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := mux.NewRouter()
+	Register(r, Deps{
+		Config:    cfg,
+		Limiter:   limiter,
+		Auth:      &service.AuthService{},
+		Alumni:    &service.AlumniService{},
+		Pekerjaan: &service.PekerjaanService{},
+		Users:     &service.UserService{},
+		Search:    &service.SearchService{},
+		Admin:     &service.AdminService{},
+		Health:    &service.HealthService{},
+		Docs:      &service.DocsService{},
+	})
+	return r
+}
+
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, stale, err := spec.Check(newTestRouter(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) > 0 {
+		t.Errorf("routes missing from openapi.yaml: %v", missing)
+	}
+	if len(stale) > 0 {
+		t.Errorf("operations in openapi.yaml without a route: %v", stale)
+	}
+}
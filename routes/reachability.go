@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// sampleID dipakai untuk mengisi variabel path saat memeriksa route.
+const sampleID = "000000000000000000000000"
+
+// Unreachable mengembalikan route yang tidak pernah terpilih oleh router
+// karena tertutup route lain yang terdaftar lebih dulu (misalnya
+// GET /pekerjaan/{alumni_id} yang menutupi GET /pekerjaan/{id}).
+func Unreachable(r *mux.Router) ([]string, error) {
+	var shadowed []string
+	err := r.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil // subrouter / prefix
+		}
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return fmt.Errorf("route %s has no method restriction", tpl)
+		}
+		names, err := route.GetVarNames()
+		if err != nil {
+			return err
+		}
+		var pairs []string
+		for _, name := range names {
+			pairs = append(pairs, name, sampleID)
+		}
+		u, err := route.URLPath(pairs...)
+		if err != nil {
+			return fmt.Errorf("build sample URL for %s: %w", tpl, err)
+		}
+
+		for _, m := range methods {
+			req, err := http.NewRequest(m, u.String(), nil)
+			if err != nil {
+				return err
+			}
+			var match mux.RouteMatch
+			if !r.Match(req, &match) || match.Route != route {
+				shadowed = append(shadowed, m+" "+tpl)
+			}
+		}
+		return nil
+	})
+	return shadowed, err
+}
@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestNoUnreachableRoutes(t *testing.T) {
+	shadowed, err := Unreachable(newTestRouter(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shadowed) > 0 {
+		t.Errorf("routes shadowed by earlier routes: %v", shadowed)
+	}
+}
+
+func TestPekerjaanRoutesReachIntendedHandler(t *testing.T) {
+	r := newTestRouter(t)
+	const id = "64b7f0c2a1b2c3d4e5f60718"
+
+	tests := []struct {
+		method, path, template string
+	}{
+		{"GET", "/api/v1/pekerjaan/" + id, "/api/v1/pekerjaan/{id}"},
+		{"PUT", "/api/v1/pekerjaan/" + id, "/api/v1/pekerjaan/{id}"},
+		{"DELETE", "/api/v1/pekerjaan/" + id, "/api/v1/pekerjaan/{id}"},
+		{"GET", "/api/v1/alumni/" + id + "/pekerjaan", "/api/v1/alumni/{id}/pekerjaan"},
+		{"DELETE", "/api/v1/alumni/" + id + "/pekerjaan", "/api/v1/alumni/{id}/pekerjaan"},
+		{"PUT", "/api/v1/trash/alumni/" + id + "/pekerjaan/restore", "/api/v1/trash/alumni/{id}/pekerjaan/restore"},
+		{"DELETE", "/api/v1/trash/alumni/" + id + "/pekerjaan/hard-delete", "/api/v1/trash/alumni/{id}/pekerjaan/hard-delete"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			var match mux.RouteMatch
+			if !r.Match(httptest.NewRequest(tt.method, tt.path, nil), &match) || match.Route == nil {
+				t.Fatalf("no route matched (err %v)", match.MatchErr)
+			}
+			tpl, _ := match.Route.GetPathTemplate()
+			if tpl != tt.template {
+				t.Errorf("matched %s, want %s", tpl, tt.template)
+			}
+			methods, _ := match.Route.GetMethods()
+			if len(methods) != 1 || methods[0] != tt.method {
+				t.Errorf("matched route for methods %v, want %s", methods, tt.method)
+			}
+			if match.Vars["id"] != id {
+				t.Errorf("id = %q, want %q", match.Vars["id"], id)
+			}
+		})
+	}
+}
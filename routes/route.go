@@ -3,136 +3,67 @@ package routes
 import (
 	middleware "crud-app/Middleware"
 	service "crud-app/app/Service"
+	"crud-app/app/ratelimit"
 	"crud-app/app/repository"
+	"crud-app/config"
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func UserRoutes(r *mux.Router, PekerjaanService *service.PekerjaanService, alumniService *service.AlumniService, authService *service.AuthService, userRepo *repository.UserRepository, userService *service.UserService) {
-	r.HandleFunc("/register", authService.Register).Methods("POST")
-	r.HandleFunc("/login", authService.Login).Methods("POST")
-
-	// Alumni routes
-	r.Handle("/alumni/{id}", middleware.AuthMiddleware(*userRepo, http.HandlerFunc(alumniService.GetByID))).Methods("GET")
-
-	r.Handle("/alumni", middleware.AuthMiddleware(*userRepo,
-		middleware.RoleMiddleware("admin", http.HandlerFunc(alumniService.Create)))).Methods("POST")
-
-	r.Handle("/alumni/{id}", middleware.AuthMiddleware(*userRepo,
-		middleware.RoleMiddleware("admin", http.HandlerFunc(alumniService.Update)))).Methods("PUT")
-
-	r.Handle("/alumni/{id}", middleware.AuthMiddleware(*userRepo,
-		middleware.RoleMiddleware("admin", http.HandlerFunc(alumniService.Delete)))).Methods("DELETE")
-
-	// Pekerjaan routes
-	r.Handle("/pekerjaan/{alumni_id}", middleware.AuthMiddleware(*userRepo, http.HandlerFunc(PekerjaanService.GetByAlumni))).Methods("GET")
-	r.Handle("/pekerjaan", middleware.AuthMiddleware(*userRepo,
-		middleware.RoleMiddleware("admin", http.HandlerFunc(PekerjaanService.Create)))).Methods("POST")
-	r.Handle("/pekerjaan/{id}", middleware.AuthMiddleware(*userRepo,
-		middleware.RoleMiddleware("admin", http.HandlerFunc(PekerjaanService.Update)))).Methods("PUT")
-	// r.Handle("/pekerjaan/{id}", middleware.AuthMiddleware(*userRepo,
-	// 	middleware.RoleMiddleware("admin", http.HandlerFunc(PekerjaanService.Delete)))).Methods("DELETE")
-	r.Handle("/pekerjaan/{id}", middleware.AuthMiddleware(*userRepo, http.HandlerFunc(PekerjaanService.GetByID))).Methods("GET")
-	
-
-	r.Handle("/Users/{id}", middleware.AuthMiddleware(*userRepo, http.HandlerFunc(userService.SoftDeleteUser))).Methods("DELETE")
-	// Routing with pagination , sort by dll
-	r.Handle("/users", middleware.AuthMiddleware(*userRepo, http.HandlerFunc(userService.GetUsers))).Methods("GET")
-	r.Handle("/alumni", middleware.AuthMiddleware(*userRepo, http.HandlerFunc(alumniService.GetAlumni))).Methods("GET")
-	r.Handle("/pekerjaan", middleware.AuthMiddleware(*userRepo, http.HandlerFunc(PekerjaanService.GetPekerjaan))).Methods("GET")
-	
-
-r.Handle("/pekerjaan/{id}", 
-    middleware.AuthMiddleware(*userRepo, http.HandlerFunc(PekerjaanService.SoftDeletePekerjaan)),
-).Methods("DELETE")
-
-
-
-// package routes
-
-// import (
-// 	middleware "crud-app/Middleware"
-// 	service "crud-app/app/Service"
-// 	"crud-app/app/repository"
-// 	"net/http"
-
-// 	"github.com/gorilla/mux"
-// )
-
-// func UserRoutes(
-// 	r *mux.Router,
-// 	PekerjaanService *service.PekerjaanService,
-// 	alumniService *service.AlumniService,
-// 	authService *service.AuthService,
-// 	userRepo *repository.UserRepository,
-// 	userService *service.UserService,
-// ) {
-// 	r.HandleFunc("/register", authService.Register).Methods("POST")
-// 	r.HandleFunc("/login", authService.Login).Methods("POST")
-
-// 	// Alumni routes
-// 	r.Handle("/alumni/{id}", middleware.AuthMiddleware(*userRepo, http.HandlerFunc(alumniService.GetByID))).Methods("GET")
-
-// 	r.Handle("/alumni", middleware.AuthMiddleware(*userRepo,
-// 		middleware.RoleMiddleware("admin", http.HandlerFunc(alumniService.Create)))).Methods("POST")
-
-// 	r.Handle("/alumni/{id}", middleware.AuthMiddleware(*userRepo,
-// 		middleware.RoleMiddleware("admin", http.HandlerFunc(alumniService.Update)))).Methods("PUT")
-
-// 	r.Handle("/alumni/{id}", middleware.AuthMiddleware(*userRepo,
-// 		middleware.RoleMiddleware("admin", http.HandlerFunc(alumniService.Delete)))).Methods("DELETE")
-
-// 	// ========================
-// 	// Pekerjaan routes
-// 	// ========================
-
-// 	// ambil semua pekerjaan milik alumni tertentu
-// 	r.Handle("/pekerjaan/{alumni_id}",
-// 		middleware.AuthMiddleware(*userRepo, http.HandlerFunc(PekerjaanService.GetByAlumni)),
-// 	).Methods("GET")
-
-// 	// tambah pekerjaan (admin only)
-// 	r.Handle("/pekerjaan",
-// 		middleware.AuthMiddleware(*userRepo, middleware.RoleMiddleware("admin", http.HandlerFunc(PekerjaanService.Create))),
-// 	).Methods("POST")
-
-// 	// update pekerjaan (admin only)
-// 	r.Handle("/pekerjaan/{id}",
-// 		middleware.AuthMiddleware(*userRepo, middleware.RoleMiddleware("admin", http.HandlerFunc(PekerjaanService.Update))),
-// 	).Methods("PUT")
-
-// 	// SOFT DELETE oleh USER (hanya hapus pekerjaannya sendiri)
-// 	r.Handle("/pekerjaan/user/{id}",
-// 		middleware.AuthMiddleware(*userRepo, http.HandlerFunc(PekerjaanService.DeleteByUser)),
-// 	).Methods("DELETE")
-
-// 	// SOFT DELETE oleh ADMIN (hapus semua pekerjaan)
-// 	r.Handle("/pekerjaan/admin/{id}",
-// 		middleware.AuthMiddleware(*userRepo, middleware.RoleMiddleware("admin", http.HandlerFunc(PekerjaanService.DeleteByAdmin))),
-// 	).Methods("DELETE")
-
-// 	// ========================
-// 	// Extra routes
-// 	// ========================
-// 	r.Handle("/users", middleware.AuthMiddleware(*userRepo, http.HandlerFunc(userService.GetUsers))).Methods("GET")
-// 	r.Handle("/alumni", middleware.AuthMiddleware(*userRepo, http.HandlerFunc(alumniService.GetAlumni))).Methods("GET")
-// 	r.Handle("/PekerjaanAlumni", middleware.AuthMiddleware(*userRepo, http.HandlerFunc(PekerjaanService.GetPekerjaan))).Methods("GET")
-// }
-	
-	// TRASH MANAGEMENT ROUTES
-	// Get trash data
-	r.Handle("/trash/pekerjaan", 
-		middleware.AuthMiddleware(*userRepo, http.HandlerFunc(PekerjaanService.GetTrash)),
-	).Methods("GET")
-	
-	// Restore from trash
-	r.Handle("/trash/pekerjaan/{id}/restore", 
-		middleware.AuthMiddleware(*userRepo, http.HandlerFunc(PekerjaanService.RestorePekerjaan)),
-	).Methods("PUT")
-	
-	// Hard delete (permanen hapus)
-	r.Handle("/trash/pekerjaan/{id}/hard-delete", 
-		middleware.AuthMiddleware(*userRepo, http.HandlerFunc(PekerjaanService.HardDeletePekerjaan)),
-	).Methods("DELETE")
-}
\ No newline at end of file
+// Deps berisi semua dependency router. Setiap versi API punya fungsi
+// registrasi sendiri (V1, nanti V2) yang dipasang di prefix masing-masing
+// dan memakai Deps yang sama.
+type Deps struct {
+	Config   *config.Config
+	UserRepo repository.UserRepository
+	Limiter  *ratelimit.Limiter
+
+	Auth      *service.AuthService
+	Alumni    *service.AlumniService
+	Pekerjaan *service.PekerjaanService
+	Users     *service.UserService
+	Search    *service.SearchService
+	Admin     *service.AdminService
+	Health    *service.HealthService
+	Docs      *service.DocsService
+}
+
+// Register memasang route operasional (tanpa versi), /api/v1 dan alias lama.
+func Register(r *mux.Router, d Deps) {
+	// Health check untuk orchestrator (tanpa auth)
+	r.HandleFunc("/healthz", d.Health.Liveness).Methods("GET")
+	r.HandleFunc("/readyz", d.Health.Readiness).Methods("GET")
+	r.Handle("/metrics", promhttp.Handler()).Methods("GET")
+
+	// Dokumentasi API (lihat app/openapi/openapi.yaml)
+	r.HandleFunc("/openapi.json", d.Docs.OpenAPI).Methods("GET")
+	r.HandleFunc("/docs", d.Docs.Docs).Methods("GET")
+	r.HandleFunc("/docs/redoc.standalone.js", d.Docs.Redoc).Methods("GET")
+
+	V1(r.PathPrefix("/api/v1").Subrouter(), d)
+	Legacy(r, d)
+}
+
+// auth mewajibkan login.
+func (d Deps) auth(h http.HandlerFunc) http.Handler {
+	return middleware.AuthMiddleware(d.UserRepo, d.Config.Auth.JWTSecret, h)
+}
+
+// admin mewajibkan login dengan role admin.
+func (d Deps) admin(h http.HandlerFunc) http.Handler {
+	return middleware.AuthMiddleware(d.UserRepo, d.Config.Auth.JWTSecret,
+		middleware.RoleMiddleware("admin", h))
+}
+
+// list untuk endpoint listing: login + rate limit per user.
+func (d Deps) list(h http.HandlerFunc) http.Handler {
+	return middleware.AuthMiddleware(d.UserRepo, d.Config.Auth.JWTSecret,
+		middleware.RateLimit(d.Limiter, "list", h))
+}
+
+// public untuk login/register: dibatasi per IP untuk mencegah brute force.
+func (d Deps) public(h http.HandlerFunc) http.Handler {
+	return middleware.RateLimit(d.Limiter, "auth", h)
+}
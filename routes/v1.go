@@ -0,0 +1,47 @@
+package routes
+
+import "github.com/gorilla/mux"
+
+// V1 memasang route /api/v1. Semua path huruf kecil.
+func V1(r *mux.Router, d Deps) {
+	// Auth
+	r.Handle("/register", d.public(d.Auth.Register)).Methods("POST")
+	r.Handle("/login", d.public(d.Auth.Login)).Methods("POST")
+	r.HandleFunc("/logout", d.Auth.Logout).Methods("POST")
+
+	// Admin: lihat konfigurasi aktif (tanpa rahasia)
+	r.Handle("/admin/config", d.admin(d.Admin.GetConfig)).Methods("GET")
+
+	// Alumni
+	r.Handle("/alumni", d.list(d.Alumni.GetAlumni)).Methods("GET")
+	r.Handle("/alumni", d.admin(d.Alumni.Create)).Methods("POST")
+	r.Handle("/alumni/{id}", d.auth(d.Alumni.GetByID)).Methods("GET")
+	r.Handle("/alumni/{id}", d.admin(d.Alumni.Update)).Methods("PUT")
+	r.Handle("/alumni/{id}", d.admin(d.Alumni.Delete)).Methods("DELETE")
+	r.Handle("/alumni/{id}/privacy", d.auth(d.Alumni.GetPrivacy)).Methods("GET")
+	r.Handle("/alumni/{id}/privacy", d.auth(d.Alumni.UpdatePrivacy)).Methods("PUT")
+
+	// Pekerjaan. {id} selalu ID pekerjaan; operasi per alumni ada di
+	// /alumni/{id}/pekerjaan.
+	r.Handle("/pekerjaan", d.list(d.Pekerjaan.GetPekerjaan)).Methods("GET")
+	r.Handle("/pekerjaan", d.admin(d.Pekerjaan.Create)).Methods("POST")
+	r.Handle("/pekerjaan/{id}", d.auth(d.Pekerjaan.GetByID)).Methods("GET")
+	r.Handle("/pekerjaan/{id}", d.admin(d.Pekerjaan.Update)).Methods("PUT")
+	r.Handle("/pekerjaan/{id}", d.auth(d.Pekerjaan.SoftDelete)).Methods("DELETE")
+	r.Handle("/alumni/{id}/pekerjaan", d.auth(d.Pekerjaan.GetByAlumni)).Methods("GET")
+	r.Handle("/alumni/{id}/pekerjaan", d.admin(d.Pekerjaan.SoftDeleteByAlumni)).Methods("DELETE")
+
+	// Trash: operasi satuan untuk pemilik/admin, operasi massal per alumni untuk admin
+	r.Handle("/trash/pekerjaan", d.list(d.Pekerjaan.GetTrash)).Methods("GET")
+	r.Handle("/trash/pekerjaan/{id}/restore", d.auth(d.Pekerjaan.Restore)).Methods("PUT")
+	r.Handle("/trash/pekerjaan/{id}/hard-delete", d.auth(d.Pekerjaan.HardDelete)).Methods("DELETE")
+	r.Handle("/trash/alumni/{id}/pekerjaan/restore", d.admin(d.Pekerjaan.RestoreByAlumni)).Methods("PUT")
+	r.Handle("/trash/alumni/{id}/pekerjaan/hard-delete", d.admin(d.Pekerjaan.HardDeleteByAlumni)).Methods("DELETE")
+
+	// Users
+	r.Handle("/users", d.list(d.Users.GetUsers)).Methods("GET")
+	r.Handle("/users/{id}", d.auth(d.Users.SoftDeleteUser)).Methods("DELETE")
+
+	// Full-text search alumni dan pekerjaan
+	r.Handle("/search", d.list(d.Search.Search)).Methods("GET")
+}